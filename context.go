@@ -0,0 +1,48 @@
+package owl
+
+import "context"
+
+type operationKey struct{}
+
+// WithOperation returns a copy of ctx carrying the current operation name.
+// Errors built with WithContext (or New with a ctx argument) pick it up as their Op.
+func WithOperation(ctx context.Context, op string) context.Context {
+	return context.WithValue(ctx, operationKey{}, op)
+}
+
+// OperationFromContext returns the operation name stored in ctx, or "".
+func OperationFromContext(ctx context.Context) string {
+	op, _ := ctx.Value(operationKey{}).(string)
+	return op
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx, or "".
+// The HTTP and gRPC middleware set it for every request.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+type loggerKey struct{}
+
+// ContextWithLogger returns a copy of ctx carrying l, e.g. a logger bound with
+// request-scoped fields. The HTTP and gRPC middleware install one per request.
+func ContextWithLogger(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// LoggerFromContext returns the logger stored in ctx, or the global logger
+// (see GetLogger) if there is none.
+func LoggerFromContext(ctx context.Context) Logger {
+	if l, ok := ctx.Value(loggerKey{}).(Logger); ok && l != nil {
+		return l
+	}
+	return GetLogger()
+}
@@ -0,0 +1,58 @@
+package owl
+
+import (
+	"context"
+	"testing"
+)
+
+func TestOperationContext(t *testing.T) {
+	ctx := context.Background()
+	if op := OperationFromContext(ctx); op != "" {
+		t.Errorf("Expected empty op, got %q", op)
+	}
+
+	ctx = WithOperation(ctx, "User.Get")
+
+	if e := Problem(CodeNotFound, WithContext(ctx)); e.Op != "User.Get" {
+		t.Errorf("Expected Op from context, got %q", e.Op)
+	}
+	if e := New(CodeNotFound, ctx, "missing"); e.Op != "User.Get" {
+		t.Errorf("Expected Op from context in New, got %q", e.Op)
+	}
+
+	// Explicit WithOp wins regardless of order
+	if e := Problem(CodeNotFound, WithOp("Explicit"), WithContext(ctx)); e.Op != "Explicit" {
+		t.Errorf("Expected explicit Op, got %q", e.Op)
+	}
+	if e := Problem(CodeNotFound, WithContext(ctx), WithOp("Explicit")); e.Op != "Explicit" {
+		t.Errorf("Expected explicit Op, got %q", e.Op)
+	}
+}
+
+// countingLogger counts Info calls.
+type countingLogger struct {
+	NoOpLogger
+	infos int
+}
+
+func (l *countingLogger) Info(ctx context.Context, msg string, args ...any) {
+	l.infos++
+}
+
+func TestLoggerFromContext(t *testing.T) {
+	global := &countingLogger{}
+	SetLogger(global)
+	defer SetLogger(NoOpLogger{})
+
+	LoggerFromContext(context.Background()).Info(context.Background(), "falls back")
+	if global.infos != 1 {
+		t.Errorf("Expected the global logger without a context logger, got %d calls", global.infos)
+	}
+
+	scoped := &countingLogger{}
+	ctx := ContextWithLogger(context.Background(), scoped)
+	LoggerFromContext(ctx).Info(ctx, "scoped")
+	if scoped.infos != 1 || global.infos != 1 {
+		t.Errorf("Expected the context logger over the global, got scoped=%d global=%d", scoped.infos, global.infos)
+	}
+}
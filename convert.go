@@ -1,13 +1,51 @@
 package owl
 
 import (
+	"context"
 	"errors"
 	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
 
+	"connectrpc.com/connect"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
+// StatusClientClosedRequest is the non-standard HTTP status (popularized by
+// nginx) used for CodeCanceled: the client went away before the response.
+const StatusClientClosedRequest = 499
+
+// CodeFromError returns the canonical code of err: CodeOK for nil, the Code of
+// the first *Error in its chain, DeadlineExceeded or Canceled for context
+// errors, or CodeInternal for any other error.
+func CodeFromError(err error) Code {
+	if err == nil {
+		return CodeOK
+	}
+	if e, ok := AsError(err); ok {
+		return e.Code
+	}
+	if code, ok := contextCode(err); ok {
+		return code
+	}
+	return CodeInternal
+}
+
+// contextCode classifies context.DeadlineExceeded and context.Canceled,
+// wrapped or not.
+func contextCode(err error) (Code, bool) {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return CodeDeadlineExceeded, true
+	case errors.Is(err, context.Canceled):
+		return CodeCanceled, true
+	}
+	return CodeUnknown, false
+}
+
 // ToHTTPStatus returns the HTTP status code for a given error.
 func ToHTTPStatus(err error) int {
 	if err == nil {
@@ -16,6 +54,9 @@ func ToHTTPStatus(err error) int {
 
 	var e *Error
 	if errors.As(err, &e) {
+		if e.HTTPStatus != 0 {
+			return e.HTTPStatus
+		}
 		switch e.Code {
 		case CodeOK:
 			return http.StatusOK
@@ -27,6 +68,14 @@ func ToHTTPStatus(err error) int {
 			return http.StatusForbidden
 		case CodeNotFound:
 			return http.StatusNotFound
+		case CodeAlreadyExists, CodeAborted:
+			return http.StatusConflict
+		case CodeFailedPrecondition:
+			return http.StatusPreconditionFailed
+		case CodeResourceExhausted:
+			return http.StatusTooManyRequests
+		case CodeCanceled:
+			return StatusClientClosedRequest
 		case CodeUnavailable:
 			return http.StatusServiceUnavailable
 		case CodeDeadlineExceeded:
@@ -35,10 +84,14 @@ func ToHTTPStatus(err error) int {
 			return http.StatusInternalServerError
 		}
 	}
+	if code, ok := contextCode(err); ok {
+		return ToHTTPStatus(Problem(code))
+	}
 	return http.StatusInternalServerError
 }
 
 // ToGRPCStatus returns the gRPC status for a given error.
+// Error.SafeDetails are attached as a google.protobuf.Struct status detail.
 func ToGRPCStatus(err error) *status.Status {
 	if err == nil {
 		return status.New(codes.OK, "OK")
@@ -58,6 +111,16 @@ func ToGRPCStatus(err error) *status.Status {
 			code = codes.PermissionDenied
 		case CodeNotFound:
 			code = codes.NotFound
+		case CodeAlreadyExists:
+			code = codes.AlreadyExists
+		case CodeFailedPrecondition:
+			code = codes.FailedPrecondition
+		case CodeResourceExhausted:
+			code = codes.ResourceExhausted
+		case CodeCanceled:
+			code = codes.Canceled
+		case CodeAborted:
+			code = codes.Aborted
 		case CodeInternal:
 			code = codes.Internal
 		case CodeUnavailable:
@@ -68,19 +131,53 @@ func ToGRPCStatus(err error) *status.Status {
 			code = codes.Unknown
 		}
 
-		msg := e.SafeMsg
-		if msg == "" {
-			msg = e.Code.String()
+		st := status.New(code, e.PublicMessage())
+		if len(e.SafeDetails) == 0 {
+			return st
 		}
 
-		return status.New(code, msg)
+		// Attach SafeDetails as a structpb.Struct so they survive the gRPC boundary.
+		details, err := structpb.NewStruct(e.SafeDetails)
+		if err == nil {
+			var withDetails *status.Status
+			withDetails, err = st.WithDetails(details)
+			if err == nil {
+				return withDetails
+			}
+		}
+		GetLogger().Warn(context.Background(), "grpc_status_details_dropped", "error", err.Error())
+		return st
 	}
 
+	if code, ok := contextCode(err); ok {
+		return ToGRPCStatus(Problem(code))
+	}
 	return status.New(codes.Unknown, "internal server error")
 }
 
+// httpStatusOverrides holds mappings added by RegisterHTTPStatusMapping.
+var httpStatusOverrides sync.Map // int -> Code
+
+// RegisterHTTPStatusMapping makes FromHTTPStatus (and so CheckResponse) map
+// status to code, extending or overriding the built-in table. This lets
+// services with custom or domain-specific statuses (e.g. 422, 451) keep their
+// meaning. It is safe for concurrent use but is meant to be called at startup.
+func RegisterHTTPStatusMapping(status int, code Code) {
+	httpStatusOverrides.Store(status, code)
+}
+
+// UnregisterHTTPStatusMapping removes a mapping added by RegisterHTTPStatusMapping,
+// restoring the built-in mapping of status (e.g. in a test's t.Cleanup).
+func UnregisterHTTPStatusMapping(status int) {
+	httpStatusOverrides.Delete(status)
+}
+
 // FromHTTPStatus converts an HTTP status code to an owl.Code.
+// Mappings registered with RegisterHTTPStatusMapping take precedence.
 func FromHTTPStatus(code int) Code {
+	if c, ok := httpStatusOverrides.Load(code); ok {
+		return c.(Code)
+	}
 	switch code {
 	case http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusNoContent:
 		return CodeOK
@@ -92,6 +189,14 @@ func FromHTTPStatus(code int) Code {
 		return CodePermissionDenied
 	case http.StatusNotFound:
 		return CodeNotFound
+	case http.StatusConflict:
+		return CodeAlreadyExists
+	case http.StatusPreconditionFailed:
+		return CodeFailedPrecondition
+	case http.StatusTooManyRequests:
+		return CodeResourceExhausted
+	case StatusClientClosedRequest:
+		return CodeCanceled
 	case http.StatusServiceUnavailable:
 		return CodeUnavailable
 	case http.StatusGatewayTimeout:
@@ -123,6 +228,16 @@ func FromGRPCStatus(code codes.Code) Code {
 		return CodePermissionDenied
 	case codes.NotFound:
 		return CodeNotFound
+	case codes.AlreadyExists:
+		return CodeAlreadyExists
+	case codes.FailedPrecondition:
+		return CodeFailedPrecondition
+	case codes.ResourceExhausted:
+		return CodeResourceExhausted
+	case codes.Canceled:
+		return CodeCanceled
+	case codes.Aborted:
+		return CodeAborted
 	case codes.Unavailable:
 		return CodeUnavailable
 	case codes.DeadlineExceeded:
@@ -133,3 +248,59 @@ func FromGRPCStatus(code codes.Code) Code {
 		return CodeUnknown
 	}
 }
+
+// FromConnectCode converts a Connect RPC error code to an owl.Code. Connect
+// codes share their values and meaning with gRPC codes, so the mapping is the
+// same as FromGRPCStatus.
+func FromConnectCode(code connect.Code) Code {
+	return FromGRPCStatus(codes.Code(code))
+}
+
+// UnknownGRPCMethod is what NormalizeGRPCMethod returns for malformed methods.
+const UnknownGRPCMethod = "unknown"
+
+// grpcMethodNormalizer holds the func set with SetGRPCMethodNormalizer.
+var grpcMethodNormalizer atomic.Value // Stores func(string) string
+
+// SetGRPCMethodNormalizer replaces the function used by NormalizeGRPCMethod,
+// e.g. to also collapse well-formed methods of services the server does not
+// register (see grpc.Server.GetServiceInfo). Passing nil restores the default.
+func SetGRPCMethodNormalizer(fn func(fullMethod string) string) {
+	grpcMethodNormalizer.Store(fn)
+}
+
+// NormalizeGRPCMethod returns a bounded metric label for a gRPC full method:
+// fullMethod itself if it has the form "/package.Service/Method", else
+// UnknownGRPCMethod. The gRPC interceptors apply it to the "method" attribute
+// and span name, so unexpected methods don't create new series.
+func NormalizeGRPCMethod(fullMethod string) string {
+	if fn, _ := grpcMethodNormalizer.Load().(func(string) string); fn != nil {
+		return fn(fullMethod)
+	}
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok || !strings.HasPrefix(fullMethod, "/") || !isIdent(method) {
+		return UnknownGRPCMethod
+	}
+	for _, part := range strings.Split(service, ".") {
+		if !isIdent(part) {
+			return UnknownGRPCMethod
+		}
+	}
+	return fullMethod
+}
+
+// isIdent reports whether s is a protobuf identifier.
+func isIdent(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, c := range s {
+		switch {
+		case c == '_', 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		case '0' <= c && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -1,10 +1,14 @@
 package owl
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"testing"
 
 	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 func TestFromHTTPStatus(t *testing.T) {
@@ -18,6 +22,9 @@ func TestFromHTTPStatus(t *testing.T) {
 		{http.StatusUnauthorized, CodeUnauthorized},
 		{http.StatusForbidden, CodePermissionDenied},
 		{http.StatusNotFound, CodeNotFound},
+		{http.StatusConflict, CodeAlreadyExists},
+		{http.StatusPreconditionFailed, CodeFailedPrecondition},
+		{http.StatusTooManyRequests, CodeResourceExhausted},
 		{http.StatusServiceUnavailable, CodeUnavailable},
 		{http.StatusGatewayTimeout, CodeDeadlineExceeded},
 		{http.StatusInternalServerError, CodeInternal},
@@ -44,6 +51,10 @@ func TestFromGRPCStatus(t *testing.T) {
 		{codes.Unauthenticated, CodeUnauthorized},
 		{codes.PermissionDenied, CodePermissionDenied},
 		{codes.NotFound, CodeNotFound},
+		{codes.AlreadyExists, CodeAlreadyExists},
+		{codes.FailedPrecondition, CodeFailedPrecondition},
+		{codes.ResourceExhausted, CodeResourceExhausted},
+		{codes.Aborted, CodeAborted},
 		{codes.Unavailable, CodeUnavailable},
 		{codes.DeadlineExceeded, CodeDeadlineExceeded},
 		{codes.Internal, CodeInternal},
@@ -58,3 +69,153 @@ func TestFromGRPCStatus(t *testing.T) {
 		}
 	}
 }
+
+func TestHTTPStatus_RoundTrip(t *testing.T) {
+	for _, status := range []int{http.StatusConflict, http.StatusPreconditionFailed, http.StatusTooManyRequests} {
+		code := FromHTTPStatus(status)
+		if got := ToHTTPStatus(Problem(code)); got != status {
+			t.Errorf("round trip of %d via %v = %d", status, code, got)
+		}
+	}
+	if got := ToHTTPStatus(Problem(CodeAborted)); got != http.StatusConflict {
+		t.Errorf("ToHTTPStatus(Aborted) = %d, want 409", got)
+	}
+}
+
+func TestFromHTTPStatus_NewCodes(t *testing.T) {
+	tests := []struct {
+		status int
+		want   Code
+	}{
+		{http.StatusConflict, CodeAlreadyExists},
+		{http.StatusPreconditionFailed, CodeFailedPrecondition},
+		{http.StatusTooManyRequests, CodeResourceExhausted},
+		{http.StatusTeapot, CodeInvalid}, // Other 4xx still fall back to Invalid
+	}
+	for _, tt := range tests {
+		if got := FromHTTPStatus(tt.status); got != tt.want {
+			t.Errorf("FromHTTPStatus(%d) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestToGRPCStatus_Details(t *testing.T) {
+	st := ToGRPCStatus(Problem(CodeNotFound,
+		WithSafeDetails(map[string]any{"resource_id": "123"}),
+		WithDetails(map[string]any{"query": "SELECT 1"}), // Internal, never attached
+	))
+	details := st.Details()
+	if len(details) != 1 {
+		t.Fatalf("Expected 1 detail, got %d", len(details))
+	}
+	s, ok := details[0].(*structpb.Struct)
+	if !ok {
+		t.Fatalf("Expected *structpb.Struct, got %T", details[0])
+	}
+	if got := s.AsMap()["resource_id"]; got != "123" {
+		t.Errorf("Expected resource_id 123, got %v", got)
+	}
+
+	// Unsupported values fall back to code + message only
+	st = ToGRPCStatus(Problem(CodeNotFound, WithSafeDetails(map[string]any{"bad": struct{}{}})))
+	if st.Code() != codes.NotFound || len(st.Details()) != 0 {
+		t.Errorf("Expected fallback status without details, got %v %v", st.Code(), st.Details())
+	}
+}
+
+func TestCodeFromError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Code
+	}{
+		{"nil", nil, CodeOK},
+		{"plain", errors.New("boom"), CodeInternal},
+		{"owl", Problem(CodeNotFound), CodeNotFound},
+		{"wrapped owl", fmt.Errorf("load: %w", Problem(CodeResourceExhausted)), CodeResourceExhausted},
+	}
+	for _, tt := range tests {
+		if got := CodeFromError(tt.err); got != tt.want {
+			t.Errorf("%s: CodeFromError() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestToHTTPStatus_Override(t *testing.T) {
+	err := Problem(CodeInvalid, WithHTTPStatus(http.StatusUnprocessableEntity))
+	if got := ToHTTPStatus(err); got != http.StatusUnprocessableEntity {
+		t.Errorf("ToHTTPStatus() = %d, want 422", got)
+	}
+	if got := ToGRPCStatus(err).Code(); got != codes.InvalidArgument {
+		t.Errorf("Expected gRPC mapping to follow Code, got %v", got)
+	}
+}
+
+func TestNormalizeGRPCMethod(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"/pkg.Service/Method", "/pkg.Service/Method"},
+		{"/grpc.health.v1.Health/Check", "/grpc.health.v1.Health/Check"},
+		{"/Service/Method_2", "/Service/Method_2"},
+		{"", UnknownGRPCMethod},
+		{"/", UnknownGRPCMethod},
+		{"pkg.Service/Method", UnknownGRPCMethod},
+		{"/pkg.Service/", UnknownGRPCMethod},
+		{"//Method", UnknownGRPCMethod},
+		{"/pkg..Service/Method", UnknownGRPCMethod},
+		{"/pkg.Service/Method/extra", UnknownGRPCMethod},
+		{"/pkg.Service/1Method", UnknownGRPCMethod},
+		{"/pkg.Service/Method?id=42", UnknownGRPCMethod},
+	}
+	for _, tt := range tests {
+		if got := NormalizeGRPCMethod(tt.in); got != tt.want {
+			t.Errorf("NormalizeGRPCMethod(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	SetGRPCMethodNormalizer(func(m string) string { return "custom" })
+	defer SetGRPCMethodNormalizer(nil)
+	if got := NormalizeGRPCMethod("/pkg.Service/Method"); got != "custom" {
+		t.Errorf("Expected the override to be used, got %q", got)
+	}
+	SetGRPCMethodNormalizer(nil)
+	if got := NormalizeGRPCMethod("/pkg.Service/Method"); got != "/pkg.Service/Method" {
+		t.Errorf("Expected nil to restore the default, got %q", got)
+	}
+}
+
+func TestContextErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		code       Code
+		httpStatus int
+		grpcCode   codes.Code
+	}{
+		{"deadline", context.DeadlineExceeded, CodeDeadlineExceeded, http.StatusGatewayTimeout, codes.DeadlineExceeded},
+		{"wrapped deadline", fmt.Errorf("query users: %w", context.DeadlineExceeded), CodeDeadlineExceeded, http.StatusGatewayTimeout, codes.DeadlineExceeded},
+		{"canceled", context.Canceled, CodeCanceled, StatusClientClosedRequest, codes.Canceled},
+		{"wrapped canceled", fmt.Errorf("read body: %w", context.Canceled), CodeCanceled, StatusClientClosedRequest, codes.Canceled},
+		// An *Error in the chain takes precedence over the context error it wraps
+		{"owl error", Problem(CodeUnavailable, WithErr(context.Canceled)), CodeUnavailable, http.StatusServiceUnavailable, codes.Unavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CodeFromError(tt.err); got != tt.code {
+				t.Errorf("CodeFromError() = %v, want %v", got, tt.code)
+			}
+			if got := ToHTTPStatus(tt.err); got != tt.httpStatus {
+				t.Errorf("ToHTTPStatus() = %d, want %d", got, tt.httpStatus)
+			}
+			if got := ToGRPCStatus(tt.err).Code(); got != tt.grpcCode {
+				t.Errorf("ToGRPCStatus() = %v, want %v", got, tt.grpcCode)
+			}
+		})
+	}
+
+	if FromGRPCStatus(codes.Canceled) != CodeCanceled || FromHTTPStatus(StatusClientClosedRequest) != CodeCanceled {
+		t.Error("Expected Canceled to round-trip through gRPC and HTTP")
+	}
+}
@@ -0,0 +1,74 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// SQLChecker returns a Checker that pings db with the check's context.
+func SQLChecker(db *sql.DB) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		if err := db.PingContext(ctx); err != nil {
+			return fmt.Errorf("database ping failed: %w", err)
+		}
+		return nil
+	})
+}
+
+// HTTPChecker returns a Checker that GETs url and fails on any non-2xx status.
+// If client is nil, http.DefaultClient is used; the check's context bounds the request.
+func HTTPChecker(url string, client *http.Client) Checker {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return CheckerFunc(func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return fmt.Errorf("invalid health check request: %w", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("GET %s failed: %w", url, err)
+		}
+		defer resp.Body.Close()
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096)) // Allow connection reuse
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("GET %s returned status %d", url, resp.StatusCode)
+		}
+		return nil
+	})
+}
+
+// TCPChecker returns a Checker that dials addr ("host:port") and closes the
+// connection immediately. A non-zero timeout caps the dial in addition to the
+// check's context.
+func TCPChecker(addr string, timeout time.Duration) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		d := net.Dialer{Timeout: timeout}
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return fmt.Errorf("tcp dial %s failed: %w", addr, err)
+		}
+		return conn.Close()
+	})
+}
+
+// DNSChecker returns a Checker that resolves host and fails if it has no addresses.
+func DNSChecker(host string) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+		if err != nil {
+			return fmt.Errorf("dns lookup %s failed: %w", host, err)
+		}
+		if len(addrs) == 0 {
+			return fmt.Errorf("dns lookup %s returned no addresses", host)
+		}
+		return nil
+	})
+}
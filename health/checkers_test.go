@@ -0,0 +1,107 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeDriver opens connections whose Ping returns pingErr.
+type fakeDriver struct {
+	pingErr error
+}
+
+func (d fakeDriver) Open(name string) (driver.Conn, error) {
+	return fakeConn{d.pingErr}, nil
+}
+
+type fakeConn struct {
+	pingErr error
+}
+
+func (c fakeConn) Ping(ctx context.Context) error          { return c.pingErr }
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+func init() {
+	sql.Register("health-ok", fakeDriver{})
+	sql.Register("health-down", fakeDriver{pingErr: errors.New("connection refused")})
+}
+
+func TestSQLChecker(t *testing.T) {
+	ok, _ := sql.Open("health-ok", "")
+	defer ok.Close()
+	if err := SQLChecker(ok).Check(context.Background()); err != nil {
+		t.Errorf("Expected healthy database, got %v", err)
+	}
+
+	down, _ := sql.Open("health-down", "")
+	defer down.Close()
+	err := SQLChecker(down).Check(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "database ping failed: connection refused") {
+		t.Errorf("Expected descriptive ping error, got %v", err)
+	}
+}
+
+func TestHTTPChecker(t *testing.T) {
+	status := http.StatusNoContent
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	checker := HTTPChecker(ts.URL, nil)
+	if err := checker.Check(context.Background()); err != nil {
+		t.Errorf("Expected 204 to be healthy, got %v", err)
+	}
+
+	status = http.StatusServiceUnavailable
+	err := checker.Check(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "returned status 503") {
+		t.Errorf("Expected status error, got %v", err)
+	}
+
+	ts.Close()
+	if err := checker.Check(context.Background()); err == nil {
+		t.Error("Expected unreachable server to be unhealthy")
+	}
+}
+
+func TestTCPChecker(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	addr := ln.Addr().String()
+
+	if err := TCPChecker(addr, time.Second).Check(context.Background()); err != nil {
+		t.Errorf("Expected listening port to be healthy, got %v", err)
+	}
+
+	ln.Close()
+	err = TCPChecker(addr, time.Second).Check(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "tcp dial "+addr+" failed") {
+		t.Errorf("Expected dial error for closed port, got %v", err)
+	}
+}
+
+func TestDNSChecker(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := DNSChecker("localhost").Check(ctx); err != nil {
+		t.Errorf("Expected localhost to resolve, got %v", err)
+	}
+	err := DNSChecker("owl-health.invalid").Check(ctx)
+	if err == nil || !strings.Contains(err.Error(), "dns lookup owl-health.invalid failed") {
+		t.Errorf("Expected lookup error, got %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCHealthServer serves the standard grpc.health.v1 Health service from Checkers.
+// Each check is exposed as a service of the same name; the empty service name
+// reports overall health, which only NOT_SERVING when a critical check fails.
+type GRPCHealthServer struct {
+	healthpb.UnimplementedHealthServer
+	checks   map[string]Checker
+	interval time.Duration
+}
+
+// GRPCServer returns a Health service backed by checks. Register it with
+// healthpb.RegisterHealthServer(s, health.GRPCServer(checks)).
+func GRPCServer(checks map[string]Checker, opts ...func(*GRPCHealthServer)) *GRPCHealthServer {
+	s := &GRPCHealthServer{
+		checks:   checks,
+		interval: 5 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// WithWatchInterval sets how often Watch streams re-run the checks (default 5s).
+func WithWatchInterval(d time.Duration) func(*GRPCHealthServer) {
+	return func(s *GRPCHealthServer) {
+		s.interval = d
+	}
+}
+
+// Check runs the checks for the requested service. Unknown services fail with NOT_FOUND.
+func (s *GRPCHealthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	st := s.status(ctx, req.GetService())
+	if st == healthpb.HealthCheckResponse_SERVICE_UNKNOWN {
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", req.GetService())
+	}
+	return &healthpb.HealthCheckResponse{Status: st}, nil
+}
+
+// List reports every check plus the overall ("") status, running each check once.
+func (s *GRPCHealthServer) List(ctx context.Context, req *healthpb.HealthListRequest) (*healthpb.HealthListResponse, error) {
+	rep := newRunner(s.checks, nil).check(ctx)
+	res := &healthpb.HealthListResponse{
+		Statuses: make(map[string]*healthpb.HealthCheckResponse, len(s.checks)+1),
+	}
+	res.Statuses[""] = &healthpb.HealthCheckResponse{Status: servingStatus(rep.Status != StatusDown)}
+	for name, result := range rep.Results {
+		res.Statuses[name] = &healthpb.HealthCheckResponse{Status: servingStatus(result == "ok")}
+	}
+	return res, nil
+}
+
+func servingStatus(serving bool) healthpb.HealthCheckResponse_ServingStatus {
+	if serving {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
+
+// Watch sends the current status, then re-polls the checks on the watch
+// interval and sends the status again whenever it changes.
+func (s *GRPCHealthServer) Watch(req *healthpb.HealthCheckRequest, stream grpc.ServerStreamingServer[healthpb.HealthCheckResponse]) error {
+	ctx := stream.Context()
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	last := healthpb.HealthCheckResponse_ServingStatus(-1)
+	for {
+		if st := s.status(ctx, req.GetService()); st != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: st}); err != nil {
+				return err
+			}
+			last = st
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// status runs the checks behind service.
+func (s *GRPCHealthServer) status(ctx context.Context, service string) healthpb.HealthCheckResponse_ServingStatus {
+	if service == "" {
+		for _, c := range s.checks {
+			if severity(c) == SeverityCritical && c.Check(ctx) != nil {
+				return healthpb.HealthCheckResponse_NOT_SERVING
+			}
+		}
+		return healthpb.HealthCheckResponse_SERVING
+	}
+
+	c, ok := s.checks[service]
+	if !ok {
+		return healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+	}
+	if c.Check(ctx) != nil {
+		return healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	return healthpb.HealthCheckResponse_SERVING
+}
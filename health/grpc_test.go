@@ -0,0 +1,132 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// watchStream collects the statuses sent on a Watch stream.
+type watchStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan healthpb.HealthCheckResponse_ServingStatus
+}
+
+func (s *watchStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *watchStream) Send(resp *healthpb.HealthCheckResponse) error {
+	s.sent <- resp.GetStatus()
+	return nil
+}
+
+func TestGRPCServer_Check(t *testing.T) {
+	srv := GRPCServer(map[string]Checker{
+		"db":    CheckerFunc(func(ctx context.Context) error { return nil }),
+		"cache": NonCritical(CheckerFunc(func(ctx context.Context) error { return errors.New("down") })),
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		service string
+		want    healthpb.HealthCheckResponse_ServingStatus
+	}{
+		{"", healthpb.HealthCheckResponse_SERVING}, // Only a non-critical check fails
+		{"db", healthpb.HealthCheckResponse_SERVING},
+		{"cache", healthpb.HealthCheckResponse_NOT_SERVING},
+	}
+	for _, tt := range tests {
+		resp, err := srv.Check(ctx, &healthpb.HealthCheckRequest{Service: tt.service})
+		if err != nil {
+			t.Fatalf("Check(%q) failed: %v", tt.service, err)
+		}
+		if resp.GetStatus() != tt.want {
+			t.Errorf("Check(%q) = %v, want %v", tt.service, resp.GetStatus(), tt.want)
+		}
+	}
+
+	if _, err := srv.Check(ctx, &healthpb.HealthCheckRequest{Service: "missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for unknown service, got %v", err)
+	}
+}
+
+func TestGRPCServer_List(t *testing.T) {
+	var calls atomic.Int32
+	srv := GRPCServer(map[string]Checker{
+		"db": CheckerFunc(func(ctx context.Context) error {
+			calls.Add(1)
+			return errors.New("down")
+		}),
+		"cache": NonCritical(CheckerFunc(func(ctx context.Context) error { return nil })),
+	})
+
+	resp, err := srv.List(context.Background(), &healthpb.HealthListRequest{})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	want := map[string]healthpb.HealthCheckResponse_ServingStatus{
+		"":      healthpb.HealthCheckResponse_NOT_SERVING,
+		"db":    healthpb.HealthCheckResponse_NOT_SERVING,
+		"cache": healthpb.HealthCheckResponse_SERVING,
+	}
+	if len(resp.GetStatuses()) != len(want) {
+		t.Errorf("Expected %d statuses, got %v", len(want), resp.GetStatuses())
+	}
+	for service, st := range want {
+		if got := resp.GetStatuses()[service].GetStatus(); got != st {
+			t.Errorf("List[%q] = %v, want %v", service, got, st)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Expected each check run once, got %d runs", n)
+	}
+}
+
+func TestGRPCServer_Watch(t *testing.T) {
+	var failing atomic.Bool
+	srv := GRPCServer(map[string]Checker{
+		"db": CheckerFunc(func(ctx context.Context) error {
+			if failing.Load() {
+				return errors.New("connection refused")
+			}
+			return nil
+		}),
+	}, WithWatchInterval(time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &watchStream{ctx: ctx, sent: make(chan healthpb.HealthCheckResponse_ServingStatus, 4)}
+	done := make(chan error, 1)
+	go func() { done <- srv.Watch(&healthpb.HealthCheckRequest{Service: "db"}, stream) }()
+
+	next := func() healthpb.HealthCheckResponse_ServingStatus {
+		select {
+		case st := <-stream.sent:
+			return st
+		case <-time.After(time.Second):
+			t.Fatal("Timeout waiting for watch update")
+			return 0
+		}
+	}
+
+	if st := next(); st != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Expected initial SERVING, got %v", st)
+	}
+	failing.Store(true)
+	if st := next(); st != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("Expected NOT_SERVING after the check flipped, got %v", st)
+	}
+
+	cancel()
+	if err := <-done; status.Code(err) != codes.Canceled {
+		t.Errorf("Expected Canceled when the stream ends, got %v", err)
+	}
+}
@@ -4,6 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"sync"
+	"time"
+
+	"github.com/myuser/owl"
 )
 
 // Checker checks the health of a component.
@@ -18,31 +22,196 @@ func (f CheckerFunc) Check(ctx context.Context) error {
 	return f(ctx)
 }
 
+// Overall health states reported in the "status" field.
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+	StatusDown     = "down"
+)
+
+// Severity of a check, reported per check in the "severity" field.
+const (
+	SeverityCritical    = "critical"
+	SeverityNonCritical = "non_critical"
+)
+
+// nonCritical marks a Checker whose failure degrades but does not fail the service.
+type nonCritical struct {
+	Checker
+}
+
+// NonCritical wraps c so that its failure reports the service as degraded
+// (200) instead of down (503). Use it for optional dependencies such as caches.
+func NonCritical(c Checker) Checker {
+	return nonCritical{c}
+}
+
+func severity(c Checker) string {
+	for {
+		switch v := c.(type) {
+		case nonCritical:
+			return SeverityNonCritical
+		case *cachedChecker:
+			c = v.Checker
+		default:
+			return SeverityCritical
+		}
+	}
+}
+
+// cachedChecker reuses the last result of Checker until ttl expires.
+type cachedChecker struct {
+	Checker
+	ttl time.Duration
+
+	mu      sync.Mutex
+	checked time.Time
+	err     error
+}
+
+// Cached wraps c so that its result is reused for ttl, protecting expensive
+// dependencies (e.g. a DB ping) from frequent load balancer probes.
+// Concurrent probes during a refresh wait for the single in-flight check.
+// A check cut short by the probe's own context (cancelled or timed out) is
+// not cached, so one aborted probe does not mark the dependency down for ttl.
+func Cached(c Checker, ttl time.Duration) Checker {
+	return &cachedChecker{Checker: c, ttl: ttl}
+}
+
+func (c *cachedChecker) Check(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.checked.IsZero() && time.Since(c.checked) < c.ttl {
+		return c.err
+	}
+	err := c.Checker.Check(ctx)
+	if err != nil && ctx.Err() != nil {
+		// The probe gave up; the outcome says nothing about the dependency
+		return err
+	}
+	c.err, c.checked = err, time.Now()
+	return err
+}
+
 // Handler returns a standard JSON health handler.
 // It iterates over the provided checks map.
-// If any check fails, it returns 503 and the error details.
-// If all pass, it returns 200.
+// If any critical check fails, it returns 503 with status "down".
+// If only NonCritical checks fail, it returns 200 with status "degraded".
+// If all pass, it returns 200 with status "ok".
+// Each check's latency is reported in the "duration_ms" field.
 func Handler(checks map[string]Checker) http.Handler {
+	return HandlerWithMonitor(checks, nil)
+}
+
+// HandlerWithMonitor is like Handler but also reports to m:
+//   - each check's latency as "health_check_duration_seconds" with a "check" attribute,
+//   - each check's result as the "health_check_up" gauge (1 or 0) with a "check" attribute,
+//   - the overall result as the "service_up" gauge, 0 only when the status is "down".
+func HandlerWithMonitor(checks map[string]Checker, m owl.Monitor) http.Handler {
+	run := newRunner(checks, m)
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		status := http.StatusOK
-		results := make(map[string]string)
+		run.check(r.Context()).write(w)
+	})
+}
+
+// report is the result of running all checks once.
+type report struct {
+	Status    string
+	Results   map[string]string
+	Severity  map[string]string
+	Durations map[string]float64
+}
+
+// httpStatus is 503 when a critical check failed, 200 otherwise.
+func (rep report) httpStatus() int {
+	if rep.Status == StatusDown {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
+// body is the JSON response document.
+func (rep report) body() map[string]any {
+	return map[string]any{
+		"ok":          rep.httpStatus() == http.StatusOK,
+		"status":      rep.Status,
+		"checks":      rep.Results,
+		"severity":    rep.Severity,
+		"duration_ms": rep.Durations,
+	}
+}
 
-		ctx := r.Context()
+func (rep report) write(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(rep.httpStatus())
+	_ = json.NewEncoder(w).Encode(rep.body())
+}
+
+// runner runs a set of checks and records their metrics.
+type runner struct {
+	checks       map[string]Checker
+	checkLatency owl.Histogram
+	checkUp      owl.Gauge
+	serviceUp    owl.Gauge
+}
+
+func newRunner(checks map[string]Checker, m owl.Monitor) *runner {
+	if m == nil {
+		m = owl.NoOpMonitor{}
+	}
+	return &runner{
+		checks: checks,
+		checkLatency: m.Histogram("health_check_duration_seconds",
+			owl.WithDescription("Duration of individual health checks."),
+			owl.WithUnit("s"),
+		),
+		checkUp: m.Gauge("health_check_up",
+			owl.WithDescription("Whether a health check passed (1) or failed (0)."),
+		),
+		serviceUp: m.Gauge("service_up",
+			owl.WithDescription("Whether the service is up (1) or down (0); degraded counts as up."),
+		),
+	}
+}
 
-		for name, checker := range checks {
-			if err := checker.Check(ctx); err != nil {
-				status = http.StatusServiceUnavailable
-				results[name] = err.Error()
-			} else {
-				results[name] = "ok"
+func (run *runner) check(ctx context.Context) report {
+	rep := report{
+		Status:    StatusOK,
+		Results:   make(map[string]string),
+		Severity:  make(map[string]string),
+		Durations: make(map[string]float64),
+	}
+
+	for name, checker := range run.checks {
+		sev := severity(checker)
+		rep.Severity[name] = sev
+
+		start := time.Now()
+		err := checker.Check(ctx)
+		elapsed := time.Since(start)
+
+		rep.Durations[name] = float64(elapsed.Microseconds()) / 1000
+		run.checkLatency.Record(ctx, elapsed.Seconds(), owl.Attr("check", name))
+
+		if err != nil {
+			rep.Results[name] = err.Error()
+			run.checkUp.Set(ctx, 0, owl.Attr("check", name))
+			if sev == SeverityCritical {
+				rep.Status = StatusDown
+			} else if rep.Status == StatusOK {
+				rep.Status = StatusDegraded
 			}
+		} else {
+			rep.Results[name] = "ok"
+			run.checkUp.Set(ctx, 1, owl.Attr("check", name))
 		}
+	}
 
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(status)
-		_ = json.NewEncoder(w).Encode(map[string]any{
-			"ok":     status == http.StatusOK,
-			"checks": results,
-		})
-	})
+	up := 1.0
+	if rep.Status == StatusDown {
+		up = 0
+	}
+	run.serviceUp.Set(ctx, up)
+	return rep
 }
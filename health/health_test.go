@@ -3,9 +3,16 @@ package health
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/myuser/owl"
+	"github.com/myuser/owl/owltest"
 )
 
 func TestHealthHandler(t *testing.T) {
@@ -37,3 +44,159 @@ func TestHealthHandler(t *testing.T) {
 		t.Errorf("Expected db ok, got %v", checks["db"])
 	}
 }
+
+func TestHealthHandler_Severity(t *testing.T) {
+	ok := CheckerFunc(func(ctx context.Context) error { return nil })
+	failing := CheckerFunc(func(ctx context.Context) error { return errors.New("connection refused") })
+
+	tests := []struct {
+		name       string
+		checks     map[string]Checker
+		wantCode   int
+		wantStatus string
+	}{
+		{"all ok", map[string]Checker{"db": ok, "cache": NonCritical(ok)}, http.StatusOK, StatusOK},
+		{"degraded", map[string]Checker{"db": ok, "cache": NonCritical(failing)}, http.StatusOK, StatusDegraded},
+		{"down", map[string]Checker{"db": failing, "cache": NonCritical(failing)}, http.StatusServiceUnavailable, StatusDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			Handler(tt.checks).ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+
+			if w.Code != tt.wantCode {
+				t.Errorf("Expected status %d, got %d", tt.wantCode, w.Code)
+			}
+
+			var body struct {
+				Status   string            `json:"status"`
+				Checks   map[string]string `json:"checks"`
+				Severity map[string]string `json:"severity"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if body.Status != tt.wantStatus {
+				t.Errorf("Expected overall status %q, got %q", tt.wantStatus, body.Status)
+			}
+			if body.Severity["db"] != SeverityCritical || body.Severity["cache"] != SeverityNonCritical {
+				t.Errorf("Unexpected severities: %v", body.Severity)
+			}
+		})
+	}
+}
+
+func TestHandlerWithMonitor_Duration(t *testing.T) {
+	monitor := owltest.NewMonitor()
+	handler := HandlerWithMonitor(map[string]Checker{
+		"db": CheckerFunc(func(ctx context.Context) error {
+			time.Sleep(5 * time.Millisecond)
+			return nil
+		}),
+	}, monitor)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+
+	var body struct {
+		DurationMS map[string]float64 `json:"duration_ms"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if body.DurationMS["db"] < 5 {
+		t.Errorf("Expected db duration >= 5ms, got %v", body.DurationMS["db"])
+	}
+
+	if n := monitor.HistogramCount("health_check_duration_seconds"); n != 1 {
+		t.Errorf("Expected 1 latency sample, got %d", n)
+	}
+	if sum := monitor.HistogramSum("health_check_duration_seconds"); sum < 0.005 {
+		t.Errorf("Expected recorded latency >= 5ms, got %vs", sum)
+	}
+}
+
+func TestHandlerWithMonitor_UpGauges(t *testing.T) {
+	monitor := owltest.NewMonitor()
+	var dbErr error
+	handler := HandlerWithMonitor(map[string]Checker{
+		"db":    CheckerFunc(func(ctx context.Context) error { return dbErr }),
+		"cache": NonCritical(CheckerFunc(func(ctx context.Context) error { return nil })),
+	}, monitor)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/health", nil))
+	if up := monitor.GetGaugeWith("health_check_up", owl.Attr("check", "db")); up != 1 {
+		t.Errorf("Expected db up, got %v", up)
+	}
+	if up := monitor.GetGauge("service_up"); up != 1 {
+		t.Errorf("Expected service up, got %v", up)
+	}
+
+	dbErr = errors.New("connection refused")
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/health", nil))
+	if up := monitor.GetGaugeWith("health_check_up", owl.Attr("check", "db")); up != 0 {
+		t.Errorf("Expected db down, got %v", up)
+	}
+	if up := monitor.GetGaugeWith("health_check_up", owl.Attr("check", "cache")); up != 1 {
+		t.Errorf("Expected cache up, got %v", up)
+	}
+	if up := monitor.GetGauge("service_up"); up != 0 {
+		t.Errorf("Expected service down, got %v", up)
+	}
+}
+
+func TestCached(t *testing.T) {
+	var calls atomic.Int32
+	checker := Cached(CheckerFunc(func(ctx context.Context) error {
+		calls.Add(1)
+		return nil
+	}), 50*time.Millisecond)
+	handler := Handler(map[string]Checker{"db": checker})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/health", nil))
+		}()
+	}
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Expected 1 check within TTL, got %d", n)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/health", nil))
+	if n := calls.Load(); n != 2 {
+		t.Errorf("Expected check to re-run after TTL, got %d calls", n)
+	}
+}
+
+func TestCached_ProbeContextErrors(t *testing.T) {
+	var calls atomic.Int32
+	checker := Cached(CheckerFunc(func(ctx context.Context) error {
+		calls.Add(1)
+		return ctx.Err()
+	}), time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := checker.Check(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the aborted probe to fail, got %v", err)
+	}
+	if err := checker.Check(context.Background()); err != nil {
+		t.Errorf("Expected the aborted outcome not to be cached, got %v", err)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("Expected the check to re-run, got %d calls", n)
+	}
+}
+
+func TestCached_KeepsSeverity(t *testing.T) {
+	if got := severity(Cached(NonCritical(CheckerFunc(nil)), time.Second)); got != SeverityNonCritical {
+		t.Errorf("Expected %q, got %q", SeverityNonCritical, got)
+	}
+}
@@ -0,0 +1,122 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/myuser/owl"
+)
+
+// Poller runs checks in the background and serves the last result, for
+// checks too expensive to run on every probe.
+type Poller struct {
+	checks     map[string]Checker
+	interval   time.Duration
+	staleAfter time.Duration
+	monitor    owl.Monitor
+
+	mu        sync.RWMutex
+	last      report
+	checkedAt time.Time
+
+	cancel context.CancelFunc
+	done   <-chan error
+}
+
+// NewPoller creates a Poller that runs checks every interval once started.
+func NewPoller(checks map[string]Checker, interval time.Duration, opts ...func(*Poller)) *Poller {
+	p := &Poller{
+		checks:     checks,
+		interval:   interval,
+		staleAfter: 3 * interval,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// WithStaleAfter sets how old the last result may be before the handler
+// reports it as stale (default 3 intervals).
+func WithStaleAfter(d time.Duration) func(*Poller) {
+	return func(p *Poller) {
+		p.staleAfter = d
+	}
+}
+
+// WithPollerMonitor records the metrics of HandlerWithMonitor on every poll,
+// so they stay fresh even when nothing requests the handler.
+func WithPollerMonitor(m owl.Monitor) func(*Poller) {
+	return func(p *Poller) {
+		p.monitor = m
+	}
+}
+
+// Start runs the checks immediately and then every interval, until ctx is
+// done or Stop is called. The polling goroutine is started with owl.GoErr, so a
+// panicking check is logged instead of crashing the process (polling stops
+// and the result goes stale). Start must be called at most once.
+func (p *Poller) Start(ctx context.Context) {
+	ctx, p.cancel = context.WithCancel(ctx)
+	run := newRunner(p.checks, p.monitor)
+
+	// GoErr always delivers a result, even when ctx is done before the
+	// goroutine runs, so Stop cannot block
+	p.done = owl.GoErr(ctx, func(ctx context.Context) error {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			rep := run.check(ctx)
+			p.mu.Lock()
+			p.last, p.checkedAt = rep, time.Now()
+			p.mu.Unlock()
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+// Stop stops polling and waits for an in-flight poll to finish.
+func (p *Poller) Stop() {
+	if p.cancel == nil {
+		return
+	}
+	p.cancel()
+	<-p.done
+}
+
+// Handler serves the last polled result in the format of Handler, plus
+// "checked_at" and "stale" fields. Before the first poll completes, and once
+// the result is stale (polling stopped or stuck), it reports the service as
+// down, since a result that old no longer says anything about the service.
+func (p *Poller) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p.mu.RLock()
+		rep, checkedAt := p.last, p.checkedAt
+		p.mu.RUnlock()
+
+		body := map[string]any{"ok": false, "status": StatusDown, "stale": true}
+		if !checkedAt.IsZero() {
+			stale := time.Since(checkedAt) > p.staleAfter
+			if stale {
+				rep.Status = StatusDown
+			}
+			body = rep.body()
+			body["checked_at"] = checkedAt.UTC().Format(time.RFC3339Nano)
+			body["stale"] = stale
+		} else {
+			rep.Status = StatusDown
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(rep.httpStatus())
+		_ = json.NewEncoder(w).Encode(body)
+	})
+}
@@ -0,0 +1,102 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// pollerBody fetches and decodes the poller's response.
+func pollerBody(t *testing.T, p *Poller) (int, map[string]any) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	p.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+	var body map[string]any
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	return w.Code, body
+}
+
+// waitFor polls cond until it holds or a second passes.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPoller(t *testing.T) {
+	var failing atomic.Bool
+	var calls atomic.Int32
+	p := NewPoller(map[string]Checker{
+		"kafka": CheckerFunc(func(ctx context.Context) error {
+			calls.Add(1)
+			if failing.Load() {
+				return errors.New("consumer lag too high")
+			}
+			return nil
+		}),
+	}, 5*time.Millisecond, WithStaleAfter(50*time.Millisecond))
+
+	// Before the first poll the service is reported down
+	if code, body := pollerBody(t, p); code != http.StatusServiceUnavailable || body["stale"] != true {
+		t.Errorf("Expected 503 and stale before start, got %d %v", code, body)
+	}
+
+	p.Start(context.Background())
+	waitFor(t, func() bool { code, _ := pollerBody(t, p); return code == http.StatusOK })
+
+	failing.Store(true)
+	waitFor(t, func() bool { code, _ := pollerBody(t, p); return code == http.StatusServiceUnavailable })
+	_, body := pollerBody(t, p)
+	if checks := body["checks"].(map[string]any); checks["kafka"] != "consumer lag too high" {
+		t.Errorf("Expected failing check result, got %v", checks)
+	}
+	if body["stale"] != false || body["checked_at"] == nil {
+		t.Errorf("Expected a fresh result, got %v", body)
+	}
+
+	// Serving does not run the checks; once stopped, the result goes stale
+	p.Stop()
+	n := calls.Load()
+	time.Sleep(60 * time.Millisecond)
+	if code, body := pollerBody(t, p); code != http.StatusServiceUnavailable || body["stale"] != true || body["ok"] != false {
+		t.Errorf("Expected a stale result to report 503 down after Stop, got %d %v", code, body)
+	}
+	if calls.Load() != n {
+		t.Error("Expected no checks to run after Stop")
+	}
+}
+
+func TestPoller_StopWithoutPolling(t *testing.T) {
+	p := NewPoller(map[string]Checker{}, time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p.Start(ctx)
+
+	stopped := make(chan struct{})
+	go func() {
+		p.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Stop blocked after Start on a cancelled context")
+	}
+
+	// Start immediately followed by Stop
+	p = NewPoller(map[string]Checker{}, time.Millisecond)
+	p.Start(context.Background())
+	p.Stop()
+}
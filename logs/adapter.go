@@ -3,9 +3,13 @@ package logs
 import (
 	"context"
 	"log/slog"
+	"math"
 	"os"
+	"runtime"
+	"strconv"
+	"time"
 
-	"go.opentelemetry.io/otel/baggage"
+	"github.com/myuser/owl"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -16,20 +20,62 @@ type Sanitizer func(key string, value any) any
 type SlogAdapter struct {
 	logger    *slog.Logger
 	sanitizer Sanitizer
+	level     *slog.LevelVar      // Minimum level, adjustable at runtime
+	source    bool                // Attach the caller's file:line as "source"
+	sampled   bool                // Drop Debug entries under unsampled spans
+	baggage   map[string]struct{} // Baggage keys to log, nil for all
+
+	// Default handler settings, only used when no *slog.Logger is supplied.
+	timeKey    string
+	timeFormat string
 }
 
 // NewSlogAdapter creates a new logger adapter.
+// If l is nil, a JSON handler writing to stdout is built from the adapter options.
 func NewSlogAdapter(l *slog.Logger, opts ...func(*SlogAdapter)) *SlogAdapter {
-	if l == nil {
-		l = slog.New(slog.NewJSONHandler(os.Stdout, nil))
-	}
 	s := &SlogAdapter{logger: l}
 	for _, opt := range opts {
 		opt(s)
 	}
+	if s.level == nil {
+		s.level = new(slog.LevelVar)
+		if s.logger != nil {
+			// Let the custom handler decide until SetLevel is called
+			s.level.Set(levelAll)
+		}
+	}
+	if s.logger == nil {
+		s.logger = slog.New(slog.NewJSONHandler(os.Stdout, s.handlerOptions()))
+	}
 	return s
 }
 
+// levelAll is below every slog level, so the adapter gate lets everything through.
+const levelAll = slog.Level(math.MinInt32)
+
+// handlerOptions builds the options for the default JSON handler.
+func (s *SlogAdapter) handlerOptions() *slog.HandlerOptions {
+	opts := &slog.HandlerOptions{Level: s.level}
+	if s.timeKey != "" || s.timeFormat != "" {
+		opts.ReplaceAttr = s.replaceTime
+	}
+	return opts
+}
+
+// replaceTime renames and reformats the top-level time attribute.
+func (s *SlogAdapter) replaceTime(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 || a.Key != slog.TimeKey || a.Value.Kind() != slog.KindTime {
+		return a
+	}
+	if s.timeFormat != "" {
+		a.Value = slog.StringValue(a.Value.Time().Format(s.timeFormat))
+	}
+	if s.timeKey != "" {
+		a.Key = s.timeKey
+	}
+	return a
+}
+
 // WithSanitizer sets the sanitizer hook.
 func WithSanitizer(fn Sanitizer) func(*SlogAdapter) {
 	return func(s *SlogAdapter) {
@@ -37,46 +83,123 @@ func WithSanitizer(fn Sanitizer) func(*SlogAdapter) {
 	}
 }
 
+// WithLevelVar sets the minimum level from a shared slog.LevelVar, so it can be
+// changed at runtime (e.g. from an admin endpoint). A custom handler's own level still applies.
+func WithLevelVar(v *slog.LevelVar) func(*SlogAdapter) {
+	return func(s *SlogAdapter) {
+		s.level = v
+	}
+}
+
+// SetLevel changes the minimum level at runtime.
+func (s *SlogAdapter) SetLevel(level slog.Level) {
+	s.level.Set(level)
+}
+
+// WithSource attaches the file:line of the code calling Debug/Info/Warn/Error as a
+// "source" attribute; leave a custom handler's AddSource off to avoid a duplicate
+// key. Wrappers around the adapter (e.g. Tee or a SamplingLogger) add frames
+// of their own, so the wrapper is reported instead.
+func WithSource(enabled bool) func(*SlogAdapter) {
+	return func(s *SlogAdapter) {
+		s.source = enabled
+	}
+}
+
+// WithBaggageAllowlist logs only the baggage members whose key is in keys, so
+// internal baggage doesn't leak into (and inflate) log entries. By default every
+// member is logged; calling it without keys logs none.
+func WithBaggageAllowlist(keys ...string) func(*SlogAdapter) {
+	return func(s *SlogAdapter) {
+		s.baggage = make(map[string]struct{}, len(keys))
+		for _, k := range keys {
+			s.baggage[k] = struct{}{}
+		}
+	}
+}
+
+// WithDebugOnlySampled drops Debug (and lower) entries logged under a span that
+// is not sampled, so verbose logs are only kept for the requests that are
+// actually traced. Entries without a span follow the normal level.
+func WithDebugOnlySampled(enabled bool) func(*SlogAdapter) {
+	return func(s *SlogAdapter) {
+		s.sampled = enabled
+	}
+}
+
+// WithTimeKey renames the timestamp field of the default handler (e.g. "@timestamp").
+// It has no effect when a custom *slog.Logger is passed to NewSlogAdapter.
+func WithTimeKey(key string) func(*SlogAdapter) {
+	return func(s *SlogAdapter) {
+		s.timeKey = key
+	}
+}
+
+// WithTimeFormat sets the time layout of the default handler (e.g. time.RFC3339Nano).
+// It has no effect when a custom *slog.Logger is passed to NewSlogAdapter.
+func WithTimeFormat(layout string) func(*SlogAdapter) {
+	return func(s *SlogAdapter) {
+		s.timeFormat = layout
+	}
+}
+
+// Enabled reports whether an entry at level would be logged with ctx: it is at
+// or above the adapter's level, the handler accepts it, and it is not a Debug
+// entry dropped by WithDebugOnlySampled.
+func (s *SlogAdapter) Enabled(ctx context.Context, level slog.Level) bool {
+	if level < s.level.Level() || !s.logger.Enabled(ctx, level) {
+		return false
+	}
+	if s.sampled && level < slog.LevelInfo {
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() && !sc.IsSampled() {
+			return false
+		}
+	}
+	return true
+}
+
 // helper to extract context
 func (s *SlogAdapter) log(ctx context.Context, level slog.Level, msg string, args ...any) {
-	// 1. Sanitize Args
-	if s.sanitizer != nil && len(args) > 1 {
-		// Args are key-value pairs (string, any)
-		for i := 0; i < len(args)-1; i += 2 {
-			key, ok := args[i].(string)
-			if ok {
-				args[i+1] = s.sanitizer(key, args[i+1])
-			}
-		}
+	// 0. Skip all the work below if the entry would be dropped
+	if !s.Enabled(ctx, level) {
+		return
 	}
 
+	// Record the application's call site rather than this frame, so a
+	// handler's AddSource is also correct. Skip Callers, log and the level method.
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+	s.logRecord(ctx, time.Now(), level, msg, pcs[0], args)
+}
+
+// logRecord writes an entry whose level was already checked, attributed to the
+// call site pc. Handler uses it to keep the time and caller of slog records.
+func (s *SlogAdapter) logRecord(ctx context.Context, t time.Time, level slog.Level, msg string, pc uintptr, args []any) {
+	// 1. Sanitize Args
+	args = SanitizeArgs(s.sanitizer, args)
+
+	// 2. Trace and baggage correlation
 	logger := s.logger
+	if fields := contextFields(ctx, s.baggage); len(fields) > 0 {
+		logger = logger.With(fields...)
+	}
 
-	// Extract TraceID
-	span := trace.SpanFromContext(ctx)
-	if span.SpanContext().IsValid() {
-		logger = logger.With(
-			slog.String("trace_id", span.SpanContext().TraceID().String()),
-			slog.String("span_id", span.SpanContext().SpanID().String()),
-		)
-	}
-
-	// Extract Baggage (Business Context)
-	bag := baggage.FromContext(ctx)
-	if bag.Len() > 0 {
-		// Baggage members to log fields
-		// We sort them for consistency or just iterate
-		// Note: Baggage iteration order is not guaranteed by default in all versions,
-		// but typically we just add them.
-		members := bag.Members()
-		// To be deterministic for testing or cleaner logs, we could sort, but OTel baggage doesn't expose a sorted slice directly easily
-		// Let's just iterate.
-		for _, member := range members {
-			logger = logger.With(slog.String(member.Key(), member.Value()))
-		}
+	// 3. Build the record at the given call site
+	r := slog.NewRecord(t, level, msg, pc)
+	if s.source && pc != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		r.AddAttrs(slog.String("source", frame.File+":"+strconv.Itoa(frame.Line)))
 	}
+	r.Add(args...)
+	_ = logger.Handler().Handle(ctx, r)
+}
 
-	logger.Log(ctx, level, msg, args...)
+// With returns an adapter whose entries carry the sanitized args.
+// It shares the level of s, so SetLevel on either applies to both.
+func (s *SlogAdapter) With(args ...any) owl.Logger {
+	c := *s
+	c.logger = s.logger.With(SanitizeArgs(s.sanitizer, args)...)
+	return &c
 }
 
 func (s *SlogAdapter) Debug(ctx context.Context, msg string, args ...any) {
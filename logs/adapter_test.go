@@ -5,8 +5,15 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/myuser/owl"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestSlogAdapter(t *testing.T) {
@@ -106,3 +113,190 @@ func TestSlogAdapter_Sanitizer(t *testing.T) {
 		t.Errorf("Expected redacted token, got %v", logEntry["token"])
 	}
 }
+
+func TestSlogAdapter_TimeKeyAndFormat(t *testing.T) {
+	var buf bytes.Buffer
+	adapter := NewSlogAdapter(nil, WithTimeKey("@timestamp"), WithTimeFormat(time.RFC3339Nano))
+
+	// Point the default handler options at a buffer instead of stdout
+	adapter.logger = slog.New(slog.NewJSONHandler(&buf, adapter.handlerOptions()))
+	adapter.Info(context.Background(), "hello")
+
+	var logEntry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &logEntry); err != nil {
+		t.Fatalf("Failed to unmarshal log: %v", err)
+	}
+
+	if _, ok := logEntry["time"]; ok {
+		t.Error("Expected default time key to be replaced")
+	}
+	ts, ok := logEntry["@timestamp"].(string)
+	if !ok {
+		t.Fatalf("Expected @timestamp string, got %v", logEntry["@timestamp"])
+	}
+	if _, err := time.Parse(time.RFC3339Nano, ts); err != nil {
+		t.Errorf("Expected RFC3339Nano timestamp, got %q", ts)
+	}
+}
+
+func TestSlogAdapter_SetLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelWarn)
+	adapter := NewSlogAdapter(logger, WithLevelVar(level))
+	ctx := context.Background()
+
+	adapter.Info(ctx, "suppressed")
+	if buf.Len() != 0 {
+		t.Fatalf("Expected info to be suppressed, got %s", buf.String())
+	}
+
+	adapter.SetLevel(slog.LevelDebug)
+	adapter.Debug(ctx, "now visible")
+	if !bytes.Contains(buf.Bytes(), []byte("now visible")) {
+		t.Errorf("Expected debug entry after SetLevel, got %s", buf.String())
+	}
+
+	buf.Reset()
+	adapter.SetLevel(slog.LevelError)
+	adapter.Warn(ctx, "suppressed again")
+	if buf.Len() != 0 {
+		t.Errorf("Expected warn to be suppressed, got %s", buf.String())
+	}
+}
+
+func TestSlogAdapter_WithSource(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{AddSource: true}))
+	adapter := NewSlogAdapter(logger)
+
+	adapter.Error(context.Background(), "failed", errors.New("boom"))
+
+	var logEntry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &logEntry); err != nil {
+		t.Fatalf("Failed to unmarshal log: %v", err)
+	}
+
+	// A handler's own AddSource points at the caller, not adapter.go
+	src, ok := logEntry["source"].(map[string]any)
+	if !ok || !strings.HasSuffix(src["file"].(string), "adapter_test.go") {
+		t.Errorf("Expected handler source in adapter_test.go, got %v", logEntry["source"])
+	}
+
+	buf.Reset()
+	adapter = NewSlogAdapter(slog.New(slog.NewJSONHandler(&buf, nil)), WithSource(true))
+	adapter.Info(context.Background(), "hello")
+	logEntry = nil
+	if err := json.Unmarshal(buf.Bytes(), &logEntry); err != nil {
+		t.Fatalf("Failed to unmarshal log: %v", err)
+	}
+	file, _, _ := strings.Cut(filepath.Base(fmt.Sprint(logEntry["source"])), ":")
+	if file != "adapter_test.go" {
+		t.Errorf("Expected source in adapter_test.go, got %v", logEntry["source"])
+	}
+}
+
+func TestSlogAdapter_With(t *testing.T) {
+	var buf bytes.Buffer
+	adapter := NewSlogAdapter(slog.New(slog.NewJSONHandler(&buf, nil)), WithSanitizer(func(key string, value any) any {
+		if key == "tenant" {
+			return "***"
+		}
+		return value
+	}))
+	logger := adapter.With("service", "billing", "tenant", "acme")
+
+	logger.Info(context.Background(), "charged", "amount", 10)
+
+	var logEntry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &logEntry); err != nil {
+		t.Fatalf("Failed to unmarshal log: %v", err)
+	}
+	if logEntry["service"] != "billing" || logEntry["amount"] != float64(10) {
+		t.Errorf("Expected bound and call fields, got %v", logEntry)
+	}
+	if logEntry["tenant"] != "***" {
+		t.Errorf("Expected bound fields to be sanitized, got %v", logEntry["tenant"])
+	}
+
+	// The parent adapter is unchanged
+	buf.Reset()
+	adapter.Info(context.Background(), "plain")
+	if bytes.Contains(buf.Bytes(), []byte("billing")) {
+		t.Errorf("Expected parent without bound fields, got %s", buf.String())
+	}
+}
+
+func TestSlogAdapter_WithDebugOnlySampled(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	adapter := NewSlogAdapter(logger, WithDebugOnlySampled(true))
+
+	spanCtx := func(flags trace.TraceFlags) context.Context {
+		return trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    trace.TraceID{1},
+			SpanID:     trace.SpanID{1},
+			TraceFlags: flags,
+		}))
+	}
+
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		debug bool // Whether Debug is expected to be written
+	}{
+		{"sampled span", spanCtx(trace.FlagsSampled), true},
+		{"unsampled span", spanCtx(0), false},
+		{"no span", context.Background(), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			adapter.Debug(tt.ctx, "cache lookup")
+			if got := strings.Contains(buf.String(), "cache lookup"); got != tt.debug {
+				t.Errorf("Debug written = %v, want %v: %q", got, tt.debug, buf.String())
+			}
+
+			buf.Reset()
+			adapter.Info(tt.ctx, "request handled")
+			if !strings.Contains(buf.String(), "request handled") {
+				t.Error("Expected Info to be written regardless of sampling")
+			}
+		})
+	}
+}
+
+func TestSlogAdapter_WithBaggageAllowlist(t *testing.T) {
+	ctx := context.Background()
+	ctx = owl.SetBaggage(ctx, "user_id", "42")
+	ctx = owl.SetBaggage(ctx, "internal_route", "shard-7")
+	ctx = owl.SetBaggage(ctx, "tenant_id", "acme")
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	NewSlogAdapter(logger, WithBaggageAllowlist("user_id", "tenant_id")).Info(ctx, "order placed")
+	if strings.Contains(buf.String(), "internal_route") {
+		t.Errorf("Expected baggage outside the allowlist to be dropped, got %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), `"tenant_id":"acme","user_id":"42"`) {
+		t.Errorf("Expected allowlisted baggage sorted by key, got %s", buf.String())
+	}
+
+	// Default: every member, in a stable order
+	for i := 0; i < 5; i++ {
+		buf.Reset()
+		NewSlogAdapter(logger).Info(ctx, "order placed")
+		if !strings.Contains(buf.String(), `"internal_route":"shard-7","tenant_id":"acme","user_id":"42"`) {
+			t.Fatalf("Expected all baggage sorted by key, got %s", buf.String())
+		}
+	}
+
+	buf.Reset()
+	NewSlogAdapter(logger, WithBaggageAllowlist()).Info(ctx, "order placed")
+	if strings.Contains(buf.String(), "user_id") {
+		t.Errorf("Expected an empty allowlist to drop all baggage, got %s", buf.String())
+	}
+}
@@ -0,0 +1,61 @@
+package logs
+
+import (
+	"context"
+	"sort"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ContextFields returns the trace/span IDs and baggage members carried by ctx
+// as key-value pairs, baggage sorted by key. Adapters for other logging
+// libraries use it so that all owl loggers emit the same correlation fields.
+func ContextFields(ctx context.Context) []any {
+	return contextFields(ctx, nil)
+}
+
+// contextFields is ContextFields keeping only the baggage members in allowed,
+// or all of them if allowed is nil.
+func contextFields(ctx context.Context, allowed map[string]struct{}) []any {
+	var fields []any
+
+	// Extract TraceID
+	sc := trace.SpanContextFromContext(ctx)
+	if sc.IsValid() {
+		fields = append(fields,
+			"trace_id", sc.TraceID().String(),
+			"span_id", sc.SpanID().String(),
+		)
+	}
+
+	// Extract Baggage (Business Context). Members come in no particular order.
+	members := baggage.FromContext(ctx).Members()
+	sort.Slice(members, func(i, j int) bool { return members[i].Key() < members[j].Key() })
+	for _, member := range members {
+		if allowed != nil {
+			if _, ok := allowed[member.Key()]; !ok {
+				continue
+			}
+		}
+		fields = append(fields, member.Key(), member.Value())
+	}
+	return fields
+}
+
+// SanitizeArgs applies fn to the values of the key-value pairs in args.
+// The caller's slice is left untouched; a sanitized copy is returned.
+func SanitizeArgs(fn Sanitizer, args []any) []any {
+	if fn == nil || len(args) < 2 {
+		return args
+	}
+	res := make([]any, len(args))
+	copy(res, args)
+	// Args are key-value pairs (string, any)
+	for i := 0; i < len(res)-1; i += 2 {
+		if key, ok := res[i].(string); ok {
+			res[i+1] = fn(key, res[i+1])
+		}
+	}
+	return res
+}
@@ -0,0 +1,165 @@
+package logs
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/myuser/owl"
+)
+
+// Handler is a slog.Handler that forwards records to an owl.Logger, so
+// libraries taking a *slog.Logger go through owl's pipeline (trace
+// correlation, sanitization):
+//
+//	lib.New(slog.New(logs.NewHandler(logger)))
+//
+// Levels below Info log at Debug, below Warn at Info, below Error at Warn, and
+// the rest at Error. Groups are flattened into dotted keys ("http.status"). On
+// Error records, an "error" or "err" attribute holding an error is passed as
+// the entry's error.
+//
+// Records the owl logger would drop are not built: see Enabled. When the owl
+// logger is a *SlogAdapter, the record's time and call site are kept, so the
+// "source" of WithSource points at the library's logging call.
+//
+// Do not install it with slog.SetDefault when the owl logger itself writes to
+// slog.Default(): records would loop back into the handler.
+type Handler struct {
+	logger owl.Logger
+	level  slog.Leveler // See WithHandlerLevel
+	prefix string       // Open groups, e.g. "http."
+}
+
+// NewHandler creates a slog.Handler forwarding to l.
+func NewHandler(l owl.Logger, opts ...func(*Handler)) *Handler {
+	h := &Handler{logger: l}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// WithHandlerLevel sets the minimum level of records to forward, for owl
+// loggers that cannot report their own (see Enabled).
+func WithHandlerLevel(level slog.Leveler) func(*Handler) {
+	return func(h *Handler) {
+		h.level = level
+	}
+}
+
+// levelLogger is implemented by owl loggers that can report whether an entry
+// at a level would be logged, such as *SlogAdapter.
+type levelLogger interface {
+	Enabled(ctx context.Context, level slog.Level) bool
+}
+
+// Enabled reports whether a record at level would be logged: it must reach
+// the WithHandlerLevel minimum, if set, and be enabled by the owl logger when
+// it implements Enabled(context.Context, slog.Level) bool like *SlogAdapter.
+// Other owl loggers are assumed to accept every level.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	if h.level != nil && level < h.level.Level() {
+		return false
+	}
+	if l, ok := h.logger.(levelLogger); ok {
+		return l.Enabled(ctx, owlLevel(level))
+	}
+	return true
+}
+
+// owlLevel returns the owl logger level a record at level is logged at.
+func owlLevel(level slog.Level) slog.Level {
+	switch {
+	case level < slog.LevelInfo:
+		return slog.LevelDebug
+	case level < slog.LevelWarn:
+		return slog.LevelInfo
+	case level < slog.LevelError:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+// Handle forwards r to the owl logger with r's context, so trace and baggage
+// fields are added as for any other entry.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	level := owlLevel(r.Level)
+	args := make([]any, 0, 2*r.NumAttrs())
+	var err error
+	r.Attrs(func(a slog.Attr) bool {
+		if level == slog.LevelError && err == nil && (a.Key == "error" || a.Key == "err") {
+			if e, ok := a.Value.Resolve().Any().(error); ok {
+				err = e
+				return true
+			}
+		}
+		args = appendAttr(args, h.prefix, a)
+		return true
+	})
+
+	// Keep the record's time and call site where the adapter supports it
+	if s, ok := h.logger.(*SlogAdapter); ok {
+		if !s.Enabled(ctx, level) {
+			return nil
+		}
+		if err != nil {
+			args = append(args, "error", err.Error())
+		}
+		s.logRecord(ctx, r.Time, level, r.Message, r.PC, args)
+		return nil
+	}
+
+	switch level {
+	case slog.LevelDebug:
+		h.logger.Debug(ctx, r.Message, args...)
+	case slog.LevelInfo:
+		h.logger.Info(ctx, r.Message, args...)
+	case slog.LevelWarn:
+		h.logger.Warn(ctx, r.Message, args...)
+	default:
+		h.logger.Error(ctx, r.Message, err, args...)
+	}
+	return nil
+}
+
+// WithAttrs returns a handler whose entries carry attrs, bound with the owl
+// logger's With under the currently open groups.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var args []any
+	for _, a := range attrs {
+		args = appendAttr(args, h.prefix, a)
+	}
+	if len(args) == 0 {
+		return h
+	}
+	return &Handler{logger: h.logger.With(args...), level: h.level, prefix: h.prefix}
+}
+
+// WithGroup returns a handler qualifying subsequent attributes with name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &Handler{logger: h.logger, level: h.level, prefix: h.prefix + name + "."}
+}
+
+// appendAttr appends a as key-value pairs, flattening groups into dotted keys
+// and dropping empty attributes as slog handlers should.
+func appendAttr(args []any, prefix string, a slog.Attr) []any {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return args
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		// A group with an empty key is inlined
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			args = appendAttr(args, prefix, ga)
+		}
+		return args
+	}
+	return append(args, prefix+a.Key, a.Value.Any())
+}
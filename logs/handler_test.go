@@ -0,0 +1,90 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/myuser/owl/owltest"
+)
+
+func TestHandler(t *testing.T) {
+	tl := owltest.NewLogger()
+	logger := slog.New(NewHandler(tl)).With("component", "cache")
+
+	logger.Debug("probing")
+	logger.WithGroup("http").Info("request", "status", 200, slog.Group("client", "ip", "10.0.0.1"))
+	logger.Warn("slow", slog.Group("", "ms", 250))
+	logger.Error("failed", "error", errors.New("boom"), "attempt", 3)
+
+	if len(tl.Entries) != 4 {
+		t.Fatalf("Expected 4 entries, got %d", len(tl.Entries))
+	}
+	for i, level := range []string{"DEBUG", "INFO", "WARN", "ERROR"} {
+		e := tl.Entries[i]
+		if e.Level != level {
+			t.Errorf("entry %d: expected level %s, got %s", i, level, e.Level)
+		}
+		if v, _ := e.Field("component"); v != "cache" {
+			t.Errorf("entry %d: expected bound component, got %v", i, e.Args)
+		}
+	}
+
+	req := tl.Find("request")
+	if v, _ := req.Field("http.status"); v != int64(200) {
+		t.Errorf("Expected grouped status, got %v", req.Args)
+	}
+	if v, _ := req.Field("http.client.ip"); v != "10.0.0.1" {
+		t.Errorf("Expected nested group, got %v", req.Args)
+	}
+	if v, _ := tl.Find("slow").Field("ms"); v != int64(250) {
+		t.Errorf("Expected inlined group, got %v", tl.Find("slow").Args)
+	}
+
+	failed := tl.Find("failed")
+	if failed.Error == nil || failed.Error.Error() != "boom" {
+		t.Errorf("Expected the error attribute as entry error, got %v", failed.Error)
+	}
+	if _, ok := failed.Field("error"); ok {
+		t.Errorf("Expected the error not to be repeated in args, got %v", failed.Args)
+	}
+	if v, _ := failed.Field("attempt"); v != int64(3) {
+		t.Errorf("Expected attempt, got %v", failed.Args)
+	}
+}
+
+func TestHandler_Level(t *testing.T) {
+	ctx := context.Background()
+
+	tl := owltest.NewLogger()
+	logger := slog.New(NewHandler(tl, WithHandlerLevel(slog.LevelInfo)))
+	logger.Debug("dropped")
+	logger.WithGroup("g").Info("kept")
+	if logger.Enabled(ctx, slog.LevelDebug) || len(tl.Entries) != 1 {
+		t.Errorf("Expected Debug below the handler level dropped, got %v", tl.Entries)
+	}
+
+	// The level of a *SlogAdapter is probed
+	var buf bytes.Buffer
+	adapter := NewSlogAdapter(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn})), WithSource(true))
+	logger = slog.New(NewHandler(adapter))
+	if logger.Enabled(ctx, slog.LevelInfo) || !logger.Enabled(ctx, slog.LevelWarn) {
+		t.Error("Expected Enabled to follow the adapter's level")
+	}
+
+	// Its record keeps the library's call site
+	logger.Warn("slow")
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to unmarshal log: %v", err)
+	}
+	file, _, _ := strings.Cut(filepath.Base(entry["source"].(string)), ":")
+	if file != "handler_test.go" {
+		t.Errorf("Expected source in handler_test.go, got %v", entry["source"])
+	}
+}
@@ -0,0 +1,73 @@
+// Package logr provides an owl.Logger backed by github.com/go-logr/logr,
+// the logging interface of Kubernetes controllers.
+package logr
+
+import (
+	"context"
+
+	gologr "github.com/go-logr/logr"
+	"github.com/myuser/owl"
+	"github.com/myuser/owl/logs"
+)
+
+// LogrAdapter implements owl.Logger using a logr.Logger.
+// Debug logs at V(1), Info at V(0). logr has no warning level, so Warn logs
+// at V(0) with a "severity" of "warning".
+type LogrAdapter struct {
+	logger    gologr.Logger
+	sanitizer logs.Sanitizer
+}
+
+// NewLogrAdapter creates a new logger adapter.
+func NewLogrAdapter(l gologr.Logger, opts ...func(*LogrAdapter)) *LogrAdapter {
+	a := &LogrAdapter{logger: l}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// WithSanitizer sets the sanitizer hook.
+func WithSanitizer(fn logs.Sanitizer) func(*LogrAdapter) {
+	return func(a *LogrAdapter) {
+		a.sanitizer = fn
+	}
+}
+
+// keysAndValues prepends trace and baggage correlation to the sanitized args.
+func (a *LogrAdapter) keysAndValues(ctx context.Context, args []any) []any {
+	args = logs.SanitizeArgs(a.sanitizer, args)
+	if fields := logs.ContextFields(ctx); len(fields) > 0 {
+		return append(fields, args...)
+	}
+	return args
+}
+
+// With returns an adapter whose entries carry the sanitized args.
+func (a *LogrAdapter) With(args ...any) owl.Logger {
+	c := *a
+	c.logger = a.logger.WithValues(logs.SanitizeArgs(a.sanitizer, args)...)
+	return &c
+}
+
+func (a *LogrAdapter) Debug(ctx context.Context, msg string, args ...any) {
+	if l := a.logger.V(1); l.Enabled() {
+		l.Info(msg, a.keysAndValues(ctx, args)...)
+	}
+}
+
+func (a *LogrAdapter) Info(ctx context.Context, msg string, args ...any) {
+	if a.logger.Enabled() {
+		a.logger.Info(msg, a.keysAndValues(ctx, args)...)
+	}
+}
+
+func (a *LogrAdapter) Warn(ctx context.Context, msg string, args ...any) {
+	if a.logger.Enabled() {
+		a.logger.Info(msg, append([]any{"severity", "warning"}, a.keysAndValues(ctx, args)...)...)
+	}
+}
+
+func (a *LogrAdapter) Error(ctx context.Context, msg string, err error, args ...any) {
+	a.logger.Error(err, msg, a.keysAndValues(ctx, args)...)
+}
@@ -0,0 +1,73 @@
+package logr
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestLogrAdapter(t *testing.T) {
+	var lines []string
+	sink := funcr.New(func(prefix, args string) {
+		lines = append(lines, args)
+	}, funcr.Options{Verbosity: 0})
+	adapter := NewLogrAdapter(sink)
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	adapter.Debug(ctx, "debugging") // V(1), dropped at verbosity 0
+	adapter.Info(ctx, "hello world", "key", "value")
+	adapter.Warn(ctx, "careful")
+	adapter.Error(ctx, "oops", errors.New("mock error"), "user", "123")
+
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %d: %v", len(lines), lines)
+	}
+	want := []string{
+		`"msg"="hello world" "trace_id"="` + sc.TraceID().String() + `" "span_id"="` + sc.SpanID().String() + `" "key"="value"`,
+		`"msg"="careful" "severity"="warning"`,
+		`"msg"="oops" "error"="mock error"`,
+	}
+	for i, w := range want {
+		if !strings.Contains(lines[i], w) {
+			t.Errorf("line %d: expected %q in %q", i, w, lines[i])
+		}
+	}
+	if !strings.Contains(lines[2], `"user"="123"`) {
+		t.Errorf("Expected error args, got %q", lines[2])
+	}
+}
+
+func TestLogrAdapter_Verbosity(t *testing.T) {
+	var lines []string
+	sink := funcr.New(func(prefix, args string) {
+		lines = append(lines, args)
+	}, funcr.Options{Verbosity: 1})
+
+	NewLogrAdapter(sink).Debug(context.Background(), "debugging")
+	if len(lines) != 1 || !strings.Contains(lines[0], `"level"=1`) {
+		t.Errorf("Expected debug at V(1), got %v", lines)
+	}
+}
+
+func TestLogrAdapter_With(t *testing.T) {
+	var lines []string
+	sink := funcr.New(func(prefix, args string) {
+		lines = append(lines, args)
+	}, funcr.Options{})
+	logger := NewLogrAdapter(sink).With("service", "billing")
+
+	logger.Info(context.Background(), "charged", "amount", 10)
+
+	if len(lines) != 1 || !strings.Contains(lines[0], `"service"="billing" "amount"=10`) {
+		t.Errorf("Expected bound fields before call fields, got %v", lines)
+	}
+}
@@ -0,0 +1,163 @@
+// Package otellog provides an owl.Logger that emits OpenTelemetry log records,
+// so logs share the OTLP pipeline of traces and metrics.
+package otellog
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/myuser/owl"
+	"github.com/myuser/owl/logs"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+)
+
+// instrumentationName identifies owl as the source of emitted records.
+const instrumentationName = "github.com/myuser/owl"
+
+// OTelLogAdapter implements owl.Logger using an OpenTelemetry log.Logger.
+// The span context of ctx is attached to each record by the SDK, which
+// correlates logs with traces by trace_id and span_id.
+type OTelLogAdapter struct {
+	logger    log.Logger
+	sanitizer logs.Sanitizer
+	bound     []log.KeyValue // Attributes added by With
+}
+
+// NewOTelLogAdapter creates a new logger adapter.
+// If lp is nil, the global LoggerProvider is used.
+func NewOTelLogAdapter(lp log.LoggerProvider, opts ...func(*OTelLogAdapter)) *OTelLogAdapter {
+	if lp == nil {
+		lp = global.GetLoggerProvider()
+	}
+	a := &OTelLogAdapter{logger: lp.Logger(instrumentationName)}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// WithSanitizer sets the sanitizer hook.
+func WithSanitizer(fn logs.Sanitizer) func(*OTelLogAdapter) {
+	return func(a *OTelLogAdapter) {
+		a.sanitizer = fn
+	}
+}
+
+func (a *OTelLogAdapter) log(ctx context.Context, severity log.Severity, text, msg string, err error, args ...any) {
+	if !a.logger.Enabled(ctx, log.EnabledParameters{Severity: severity}) {
+		return
+	}
+
+	args = logs.SanitizeArgs(a.sanitizer, args)
+
+	var r log.Record
+	r.SetTimestamp(time.Now())
+	r.SetSeverity(severity)
+	r.SetSeverityText(text)
+	r.SetBody(log.StringValue(msg))
+	r.AddAttributes(a.bound...)
+	r.AddAttributes(toKeyValues(args)...)
+	if err != nil {
+		r.AddAttributes(log.String("error", err.Error()))
+	}
+	a.logger.Emit(ctx, r)
+}
+
+// toKeyValues converts key-value pairs into log attributes.
+// A non-string key or a dangling value is reported under "!BADKEY", like slog does.
+func toKeyValues(args []any) []log.KeyValue {
+	if len(args) == 0 {
+		return nil
+	}
+	kvs := make([]log.KeyValue, 0, (len(args)+1)/2)
+	for i := 0; i < len(args); i += 2 {
+		key, ok := args[i].(string)
+		if !ok || i+1 >= len(args) {
+			kvs = append(kvs, log.KeyValue{Key: "!BADKEY", Value: toValue(args[i])})
+			i--
+			continue
+		}
+		kvs = append(kvs, log.KeyValue{Key: key, Value: toValue(args[i+1])})
+	}
+	return kvs
+}
+
+// toValue converts an arbitrary value into a log.Value, falling back to its string form.
+func toValue(v any) log.Value {
+	switch v := v.(type) {
+	case nil:
+		return log.Value{}
+	case string:
+		return log.StringValue(v)
+	case bool:
+		return log.BoolValue(v)
+	case int:
+		return log.IntValue(v)
+	case int8:
+		return log.Int64Value(int64(v))
+	case int16:
+		return log.Int64Value(int64(v))
+	case int32:
+		return log.Int64Value(int64(v))
+	case int64:
+		return log.Int64Value(v)
+	case uint8:
+		return log.Int64Value(int64(v))
+	case uint16:
+		return log.Int64Value(int64(v))
+	case uint32:
+		return log.Int64Value(int64(v))
+	case float32:
+		return log.Float64Value(float64(v))
+	case float64:
+		return log.Float64Value(v)
+	case []byte:
+		return log.BytesValue(v)
+	case time.Duration:
+		return log.Float64Value(v.Seconds())
+	case time.Time:
+		return log.StringValue(v.Format(time.RFC3339Nano))
+	case error:
+		return log.StringValue(v.Error())
+	case map[string]any:
+		kvs := make([]log.KeyValue, 0, len(v))
+		for k, val := range v {
+			kvs = append(kvs, log.KeyValue{Key: k, Value: toValue(val)})
+		}
+		return log.MapValue(kvs...)
+	case []any:
+		vals := make([]log.Value, len(v))
+		for i, val := range v {
+			vals[i] = toValue(val)
+		}
+		return log.SliceValue(vals...)
+	default:
+		return log.StringValue(fmt.Sprint(v))
+	}
+}
+
+// With returns an adapter whose records carry the sanitized args.
+func (a *OTelLogAdapter) With(args ...any) owl.Logger {
+	c := *a
+	kvs := toKeyValues(logs.SanitizeArgs(a.sanitizer, args))
+	c.bound = append(a.bound[:len(a.bound):len(a.bound)], kvs...)
+	return &c
+}
+
+func (a *OTelLogAdapter) Debug(ctx context.Context, msg string, args ...any) {
+	a.log(ctx, log.SeverityDebug, "DEBUG", msg, nil, args...)
+}
+
+func (a *OTelLogAdapter) Info(ctx context.Context, msg string, args ...any) {
+	a.log(ctx, log.SeverityInfo, "INFO", msg, nil, args...)
+}
+
+func (a *OTelLogAdapter) Warn(ctx context.Context, msg string, args ...any) {
+	a.log(ctx, log.SeverityWarn, "WARN", msg, nil, args...)
+}
+
+func (a *OTelLogAdapter) Error(ctx context.Context, msg string, err error, args ...any) {
+	a.log(ctx, log.SeverityError, "ERROR", msg, err, args...)
+}
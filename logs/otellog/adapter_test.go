@@ -0,0 +1,98 @@
+package otellog
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// memoryExporter keeps exported records in memory.
+type memoryExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (e *memoryExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+	return nil
+}
+
+func (e *memoryExporter) Shutdown(ctx context.Context) error   { return nil }
+func (e *memoryExporter) ForceFlush(ctx context.Context) error { return nil }
+
+func attrs(r sdklog.Record) map[string]log.Value {
+	res := map[string]log.Value{}
+	r.WalkAttributes(func(kv log.KeyValue) bool {
+		res[kv.Key] = kv.Value
+		return true
+	})
+	return res
+}
+
+func TestOTelLogAdapter(t *testing.T) {
+	exp := &memoryExporter{}
+	lp := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exp)))
+	adapter := NewOTelLogAdapter(lp)
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	adapter.Info(ctx, "hello world", "key", "value", "count", 3)
+	adapter.Error(ctx, "oops", errors.New("mock error"), "user", "123")
+
+	if len(exp.records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(exp.records))
+	}
+
+	info := exp.records[0]
+	if info.Severity() != log.SeverityInfo || info.Body().AsString() != "hello world" {
+		t.Errorf("Unexpected record: %v %v", info.Severity(), info.Body())
+	}
+	if info.TraceID() != sc.TraceID() || info.SpanID() != sc.SpanID() {
+		t.Errorf("Expected span context on record, got %s/%s", info.TraceID(), info.SpanID())
+	}
+	a := attrs(info)
+	if a["key"].AsString() != "value" || a["count"].AsInt64() != 3 {
+		t.Errorf("Unexpected attributes: %v", a)
+	}
+
+	errRec := exp.records[1]
+	if errRec.Severity() != log.SeverityError {
+		t.Errorf("Expected error severity, got %v", errRec.Severity())
+	}
+	if got := attrs(errRec)["error"].AsString(); got != "mock error" {
+		t.Errorf("Expected error attribute, got %q", got)
+	}
+}
+
+func TestOTelLogAdapter_With(t *testing.T) {
+	exp := &memoryExporter{}
+	lp := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exp)))
+	adapter := NewOTelLogAdapter(lp)
+
+	adapter.With("service", "billing").Info(context.Background(), "charged", "amount", 10)
+	adapter.Info(context.Background(), "plain")
+
+	if len(exp.records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(exp.records))
+	}
+	got := attrs(exp.records[0])
+	if got["service"].AsString() != "billing" || got["amount"].AsInt64() != 10 {
+		t.Errorf("Expected bound and call attributes, got %v", got)
+	}
+	if _, ok := attrs(exp.records[1])["service"]; ok {
+		t.Error("Expected parent adapter without bound attributes")
+	}
+}
@@ -0,0 +1,197 @@
+package logs
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces values masked by RedactKeys.
+const Redacted = "[REDACTED]"
+
+// RedactKeys returns a Sanitizer that masks values stored under any of keys
+// (case-insensitive), including keys of nested maps and struct fields.
+func RedactKeys(keys ...string) Sanitizer {
+	set := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		set[strings.ToLower(k)] = struct{}{}
+	}
+	return func(key string, value any) any {
+		return walk(key, value, func(k string, v any) (any, bool) {
+			if _, ok := set[strings.ToLower(k)]; ok {
+				return Redacted, true
+			}
+			return nil, false
+		})
+	}
+}
+
+// RedactPattern returns a Sanitizer that replaces matches of re in string values,
+// including strings nested in maps, slices and struct fields.
+func RedactPattern(re *regexp.Regexp, replacement string) Sanitizer {
+	return func(key string, value any) any {
+		return walk(key, value, func(k string, v any) (any, bool) {
+			if s, ok := v.(string); ok {
+				r := re.ReplaceAllString(s, replacement)
+				return r, r != s
+			}
+			return nil, false
+		})
+	}
+}
+
+// ChainSanitizers returns a Sanitizer applying each of sanitizers in order.
+func ChainSanitizers(sanitizers ...Sanitizer) Sanitizer {
+	return func(key string, value any) any {
+		for _, s := range sanitizers {
+			if s != nil {
+				value = s(key, value)
+			}
+		}
+		return value
+	}
+}
+
+// maxWalkDepth bounds how deep walk descends into nested values.
+const maxWalkDepth = 32
+
+// truncated replaces values walk does not descend into: references back to a
+// value being walked (cycles), and values nested deeper than maxWalkDepth.
+// Replacing them keeps unredacted originals out of the rebuilt copy.
+const truncated = "[TRUNCATED]"
+
+// walk visits value and its nested map entries, slice elements and struct fields.
+// visit is called on every node first; if it reports done, its result replaces the node.
+func walk(key string, value any, visit func(key string, value any) (any, bool)) any {
+	w := walker{visit: visit, path: make(map[walkRef]struct{})}
+	res, _ := w.walkValue(key, value, 0)
+	return res
+}
+
+// walkRef identifies a pointer, map or slice on the path being walked.
+type walkRef struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+type walker struct {
+	visit func(key string, value any) (any, bool)
+	path  map[walkRef]struct{} // References from the root to the current node
+}
+
+// enter records the reference of rv on the current path. It reports false if
+// rv is already on it, i.e. the value refers back to one of its parents.
+func (w *walker) enter(rv reflect.Value) (walkRef, bool) {
+	ref := walkRef{ptr: rv.Pointer(), typ: rv.Type()}
+	if _, cyclic := w.path[ref]; cyclic {
+		return ref, false
+	}
+	w.path[ref] = struct{}{}
+	return ref, true
+}
+
+// walkValue implements walk and reports whether anything was replaced.
+// Containers holding a replaced node are rebuilt as map[string]any or []any,
+// so the caller's values are never mutated; untouched values are returned as is.
+func (w *walker) walkValue(key string, value any, depth int) (any, bool) {
+	if res, done := w.visit(key, value); done {
+		return res, true
+	}
+	if value == nil {
+		return nil, false
+	}
+
+	switch value.(type) {
+	case error, fmt.Stringer, json.Marshaler, []byte:
+		// Opaque to the walker: they control their own representation.
+		return value, false
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Array, reflect.Struct, reflect.Interface:
+		if depth >= maxWalkDepth {
+			return truncated, true
+		}
+	}
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		if !rv.IsNil() && (rv.Kind() != reflect.Slice || rv.Len() > 0) {
+			ref, ok := w.enter(rv)
+			if !ok {
+				return truncated, true
+			}
+			defer delete(w.path, ref)
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return value, false
+		}
+		res, changed := w.walkValue(key, rv.Elem().Interface(), depth+1)
+		if !changed {
+			return value, false
+		}
+		return res, true
+
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return value, false
+		}
+		res := make(map[string]any, rv.Len())
+		changed := false
+		iter := rv.MapRange()
+		for iter.Next() {
+			k := iter.Key().String()
+			v, c := w.walkValue(k, iter.Value().Interface(), depth+1)
+			res[k] = v
+			changed = changed || c
+		}
+		if !changed {
+			return value, false
+		}
+		return res, true
+
+	case reflect.Slice, reflect.Array:
+		res := make([]any, rv.Len())
+		changed := false
+		for i := range res {
+			v, c := w.walkValue(key, rv.Index(i).Interface(), depth+1)
+			res[i] = v
+			changed = changed || c
+		}
+		if !changed {
+			return value, false
+		}
+		return res, true
+
+	case reflect.Struct:
+		rt := rv.Type()
+		res := make(map[string]any, rt.NumField())
+		changed := false
+		for i := 0; i < rt.NumField(); i++ {
+			field := rt.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := field.Name
+			if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag == "-" {
+				continue
+			} else if tag != "" {
+				name = tag
+			}
+			v, c := w.walkValue(name, rv.Field(i).Interface(), depth+1)
+			res[name] = v
+			changed = changed || c
+		}
+		if !changed {
+			return value, false
+		}
+		return res, true
+	}
+	return value, false
+}
@@ -0,0 +1,125 @@
+package logs
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+type credentials struct {
+	User     string `json:"user"`
+	Password string `json:"password"`
+	internal string
+}
+
+func TestRedactKeys(t *testing.T) {
+	s := RedactKeys("password", "Token")
+
+	if got := s("token", "secret"); got != Redacted {
+		t.Errorf("Expected top-level key redacted, got %v", got)
+	}
+	if got := s("user", "alice"); got != "alice" {
+		t.Errorf("Expected untouched value, got %v", got)
+	}
+
+	nested := map[string]any{
+		"user":  "alice",
+		"auth":  map[string]any{"password": "hunter2"},
+		"items": []any{map[string]string{"token": "abc"}},
+	}
+	got := s("payload", nested)
+	want := map[string]any{
+		"user":  "alice",
+		"auth":  map[string]any{"password": Redacted},
+		"items": []any{map[string]any{"token": Redacted}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Nested redaction = %#v, want %#v", got, want)
+	}
+	if nested["auth"].(map[string]any)["password"] != "hunter2" {
+		t.Error("Caller's map was mutated")
+	}
+
+	got = s("creds", credentials{User: "alice", Password: "hunter2"})
+	want = map[string]any{"user": "alice", "password": Redacted}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Struct redaction = %#v, want %#v", got, want)
+	}
+
+	// Values without sensitive keys keep their original type
+	now := time.Now()
+	if got := s("at", now); got != now {
+		t.Errorf("Expected time.Time untouched, got %#v", got)
+	}
+	plain := credentials{User: "bob"}
+	if got := RedactKeys("token")("creds", plain); got != plain {
+		t.Errorf("Expected struct untouched, got %#v", got)
+	}
+}
+
+type node struct {
+	Password string `json:"password"`
+	Next     *node  `json:"next"`
+}
+
+func TestRedactKeys_Cyclic(t *testing.T) {
+	s := RedactKeys("password")
+
+	n := &node{Password: "hunter2"}
+	n.Next = n
+	got, ok := s("user", n).(map[string]any)
+	if !ok || got["password"] != Redacted || got["next"] != truncated {
+		t.Errorf("Expected cycle cut after redaction, got %v", got)
+	}
+
+	m := map[string]any{"password": "hunter2"}
+	m["self"] = m
+	if got := s("args", m).(map[string]any); got["password"] != Redacted || got["self"] != truncated {
+		t.Errorf("Expected cyclic map cut, got %v", got)
+	}
+
+	// A value referenced twice without a cycle is walked both times
+	shared := &node{Password: "hunter2"}
+	pair := []*node{shared, shared}
+	if got := s("pair", pair).([]any); got[1].(map[string]any)["password"] != Redacted {
+		t.Errorf("Expected shared value redacted, got %v", got)
+	}
+}
+
+func TestRedactPattern(t *testing.T) {
+	email := regexp.MustCompile(`[\w.+-]+@[\w-]+\.[\w.]+`)
+	s := RedactPattern(email, "<email>")
+
+	if got := s("msg", "contact bob@example.com now"); got != "contact <email> now" {
+		t.Errorf("Unexpected redaction: %v", got)
+	}
+
+	got := s("users", []string{"a@b.io", "nobody"})
+	want := []any{"<email>", "nobody"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Slice redaction = %#v, want %#v", got, want)
+	}
+
+	// Values without a match keep their type
+	type user struct {
+		Name string `json:"name,omitempty"`
+	}
+	for _, v := range []any{[]string{"nobody"}, user{Name: "bob"}} {
+		if got := s("v", v); !reflect.DeepEqual(got, v) {
+			t.Errorf("Expected %#v returned as is, got %#v", v, got)
+		}
+	}
+}
+
+func TestChainSanitizers(t *testing.T) {
+	digits := regexp.MustCompile(`\d{4}`)
+	s := ChainSanitizers(RedactKeys("password"), RedactPattern(digits, "####"), nil)
+
+	if got := s("password", "1234"); got != Redacted {
+		t.Errorf("Expected key redaction first, got %v", got)
+	}
+	if got := s("card", "4111 1111"); got != "#### ####" {
+		t.Errorf("Expected pattern redaction, got %v", got)
+	}
+}
@@ -0,0 +1,107 @@
+package logs
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	"github.com/myuser/owl"
+)
+
+// SamplingLogger wraps an owl.Logger and emits only 1 in N Debug and Info
+// messages, counted per message string. Warn and Error always pass.
+//
+// Counters are keyed by msg, so messages should be constant event names
+// (e.g. "request_success") rather than formatted strings. To bound memory, the
+// counters of a level are reset once they track maxSampledMessages messages;
+// the next occurrence of each message is then emitted as a first one.
+type SamplingLogger struct {
+	next  owl.Logger
+	debug uint64 // Emit 1 in debug Debug messages; 0 or 1 disables sampling
+	info  uint64 // Emit 1 in info Info messages; 0 or 1 disables sampling
+
+	debugCounts *sampleCounts
+	infoCounts  *sampleCounts
+}
+
+// maxSampledMessages bounds the distinct messages counted per level.
+const maxSampledMessages = 1024
+
+// sampleCounts counts occurrences per message, for at most maxSampledMessages
+// messages at a time.
+type sampleCounts struct {
+	counts sync.Map // msg -> *atomic.Uint64
+	size   atomic.Int64
+}
+
+// NewSamplingLogger wraps next. Without WithSampleRate nothing is sampled.
+func NewSamplingLogger(next owl.Logger, opts ...func(*SamplingLogger)) *SamplingLogger {
+	if next == nil {
+		next = owl.NoOpLogger{}
+	}
+	s := &SamplingLogger{next: next, debugCounts: new(sampleCounts), infoCounts: new(sampleCounts)}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// WithSampleRate emits 1 in n messages of level (slog.LevelDebug or
+// slog.LevelInfo); the first occurrence of each message is always emitted.
+// Rates for other levels are ignored, since warnings and errors are never sampled.
+func WithSampleRate(level slog.Level, n uint64) func(*SamplingLogger) {
+	return func(s *SamplingLogger) {
+		switch level {
+		case slog.LevelDebug:
+			s.debug = n
+		case slog.LevelInfo:
+			s.info = n
+		}
+	}
+}
+
+// sample reports whether this occurrence of msg should be emitted.
+func sample(counts *sampleCounts, n uint64, msg string) bool {
+	if n <= 1 {
+		return true
+	}
+	c, ok := counts.counts.Load(msg)
+	if !ok {
+		// Start over rather than grow without bound on dynamic messages
+		if counts.size.Add(1) > maxSampledMessages {
+			counts.counts.Clear()
+			counts.size.Store(1)
+		}
+		c, _ = counts.counts.LoadOrStore(msg, new(atomic.Uint64))
+	}
+	return (c.(*atomic.Uint64).Add(1)-1)%n == 0
+}
+
+// With binds args on the wrapped logger. The derived logger shares the sample
+// counters of s, so a message is sampled the same way whichever logger emits it.
+func (s *SamplingLogger) With(args ...any) owl.Logger {
+	c := *s
+	c.next = s.next.With(args...)
+	return &c
+}
+
+func (s *SamplingLogger) Debug(ctx context.Context, msg string, args ...any) {
+	if sample(s.debugCounts, s.debug, msg) {
+		s.next.Debug(ctx, msg, args...)
+	}
+}
+
+func (s *SamplingLogger) Info(ctx context.Context, msg string, args ...any) {
+	if sample(s.infoCounts, s.info, msg) {
+		s.next.Info(ctx, msg, args...)
+	}
+}
+
+func (s *SamplingLogger) Warn(ctx context.Context, msg string, args ...any) {
+	s.next.Warn(ctx, msg, args...)
+}
+
+func (s *SamplingLogger) Error(ctx context.Context, msg string, err error, args ...any) {
+	s.next.Error(ctx, msg, err, args...)
+}
@@ -0,0 +1,68 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/myuser/owl/owltest"
+)
+
+func TestSamplingLogger(t *testing.T) {
+	rec := owltest.NewLogger()
+	logger := NewSamplingLogger(rec, WithSampleRate(slog.LevelInfo, 100))
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.Info(ctx, "request_success")
+			}
+		}()
+	}
+	wg.Wait()
+	logger.Info(ctx, "other_event")
+	logger.Error(ctx, "request_failed", errors.New("boom"))
+	logger.Debug(ctx, "debug_event") // Debug is not sampled unless configured
+
+	n := 0
+	for _, e := range rec.Entries {
+		if e.Msg == "request_success" {
+			n++
+		}
+	}
+	if n != 10 {
+		t.Errorf("Expected 10 sampled entries, got %d", n)
+	}
+	if rec.Find("other_event") == nil {
+		t.Error("Expected the first occurrence of a new message to pass")
+	}
+	if rec.CountLevel("ERROR") != 1 {
+		t.Error("Expected Error to always pass")
+	}
+	if rec.CountLevel("DEBUG") != 1 {
+		t.Error("Expected Debug to pass without a debug rate")
+	}
+}
+
+func TestSamplingLogger_BoundedCounters(t *testing.T) {
+	logger := NewSamplingLogger(owltest.NewLogger(), WithSampleRate(slog.LevelInfo, 10))
+	for i := 0; i < 3*maxSampledMessages; i++ {
+		logger.Info(context.Background(), fmt.Sprintf("user %d logged in", i))
+	}
+
+	n := 0
+	logger.infoCounts.counts.Range(func(_, _ any) bool {
+		n++
+		return true
+	})
+	if n > maxSampledMessages {
+		t.Errorf("Expected at most %d counters, got %d", maxSampledMessages, n)
+	}
+}
@@ -0,0 +1,55 @@
+package logs
+
+import (
+	"context"
+
+	"github.com/myuser/owl"
+)
+
+// teeLogger forwards every call to several loggers.
+type teeLogger []owl.Logger
+
+// Tee returns an owl.Logger that forwards each call to all loggers, in order.
+// A panic in one logger is recovered so the others still receive the entry.
+// Nil loggers are skipped.
+func Tee(loggers ...owl.Logger) owl.Logger {
+	t := make(teeLogger, 0, len(loggers))
+	for _, l := range loggers {
+		if l != nil {
+			t = append(t, l)
+		}
+	}
+	return t
+}
+
+func (t teeLogger) each(fn func(owl.Logger)) {
+	for _, l := range t {
+		func() {
+			defer func() { recover() }() // Keep a broken sink from silencing the others
+			fn(l)
+		}()
+	}
+}
+
+// With binds args on every logger. A logger whose With panics is dropped.
+func (t teeLogger) With(args ...any) owl.Logger {
+	derived := make(teeLogger, 0, len(t))
+	t.each(func(l owl.Logger) { derived = append(derived, l.With(args...)) })
+	return derived
+}
+
+func (t teeLogger) Debug(ctx context.Context, msg string, args ...any) {
+	t.each(func(l owl.Logger) { l.Debug(ctx, msg, args...) })
+}
+
+func (t teeLogger) Info(ctx context.Context, msg string, args ...any) {
+	t.each(func(l owl.Logger) { l.Info(ctx, msg, args...) })
+}
+
+func (t teeLogger) Warn(ctx context.Context, msg string, args ...any) {
+	t.each(func(l owl.Logger) { l.Warn(ctx, msg, args...) })
+}
+
+func (t teeLogger) Error(ctx context.Context, msg string, err error, args ...any) {
+	t.each(func(l owl.Logger) { l.Error(ctx, msg, err, args...) })
+}
@@ -0,0 +1,39 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/myuser/owl"
+	"github.com/myuser/owl/owltest"
+)
+
+// panicLogger panics on every call.
+type panicLogger struct {
+	owl.NoOpLogger
+}
+
+func (panicLogger) Info(ctx context.Context, msg string, args ...any) {
+	panic("sink down")
+}
+
+func TestTee(t *testing.T) {
+	first, second := owltest.NewLogger(), owltest.NewLogger()
+	logger := Tee(first, panicLogger{}, nil, second)
+	ctx := context.Background()
+
+	logger.Info(ctx, "hello", "key", "value")
+	logger.Error(ctx, "oops", errors.New("boom"), "user", "123")
+
+	if !reflect.DeepEqual(first.Entries, second.Entries) {
+		t.Errorf("Expected identical entries, got %v and %v", first.Entries, second.Entries)
+	}
+	if len(second.Entries) != 2 {
+		t.Fatalf("Expected 2 entries after a panicking logger, got %d", len(second.Entries))
+	}
+	if e := second.Entries[0]; e.Level != "INFO" || e.Msg != "hello" || e.Args[1] != "value" {
+		t.Errorf("Unexpected entry: %+v", e)
+	}
+}
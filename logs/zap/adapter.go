@@ -0,0 +1,97 @@
+// Package zap provides an owl.Logger backed by go.uber.org/zap.
+package zap
+
+import (
+	"context"
+
+	"github.com/myuser/owl"
+	"github.com/myuser/owl/logs"
+	uberzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ZapAdapter implements owl.Logger using a *zap.Logger.
+type ZapAdapter struct {
+	logger    *uberzap.Logger
+	sanitizer logs.Sanitizer
+}
+
+// NewZapAdapter creates a new logger adapter.
+// If l is nil, zap.NewProduction is used (falling back to a no-op logger).
+func NewZapAdapter(l *uberzap.Logger, opts ...func(*ZapAdapter)) *ZapAdapter {
+	if l == nil {
+		var err error
+		if l, err = uberzap.NewProduction(); err != nil {
+			l = uberzap.NewNop()
+		}
+	}
+	z := &ZapAdapter{logger: l}
+	for _, opt := range opts {
+		opt(z)
+	}
+	return z
+}
+
+// WithSanitizer sets the sanitizer hook.
+func WithSanitizer(fn logs.Sanitizer) func(*ZapAdapter) {
+	return func(z *ZapAdapter) {
+		z.sanitizer = fn
+	}
+}
+
+func (z *ZapAdapter) log(ctx context.Context, level zapcore.Level, msg string, err error, args ...any) {
+	ce := z.logger.Check(level, msg)
+	if ce == nil {
+		return
+	}
+
+	args = logs.SanitizeArgs(z.sanitizer, args)
+	fields := toFields(logs.ContextFields(ctx))
+	fields = append(fields, toFields(args)...)
+	if err != nil {
+		fields = append(fields, uberzap.Error(err))
+	}
+	ce.Write(fields...)
+}
+
+// toFields converts key-value pairs into zap fields.
+// A non-string key or a dangling value is reported under "!BADKEY", like slog does.
+func toFields(args []any) []zapcore.Field {
+	if len(args) == 0 {
+		return nil
+	}
+	fields := make([]zapcore.Field, 0, (len(args)+1)/2)
+	for i := 0; i < len(args); i += 2 {
+		key, ok := args[i].(string)
+		if !ok || i+1 >= len(args) {
+			fields = append(fields, uberzap.Any("!BADKEY", args[i]))
+			i--
+			continue
+		}
+		fields = append(fields, uberzap.Any(key, args[i+1]))
+	}
+	return fields
+}
+
+// With returns an adapter whose entries carry the sanitized args.
+func (z *ZapAdapter) With(args ...any) owl.Logger {
+	c := *z
+	c.logger = z.logger.With(toFields(logs.SanitizeArgs(z.sanitizer, args))...)
+	return &c
+}
+
+func (z *ZapAdapter) Debug(ctx context.Context, msg string, args ...any) {
+	z.log(ctx, zapcore.DebugLevel, msg, nil, args...)
+}
+
+func (z *ZapAdapter) Info(ctx context.Context, msg string, args ...any) {
+	z.log(ctx, zapcore.InfoLevel, msg, nil, args...)
+}
+
+func (z *ZapAdapter) Warn(ctx context.Context, msg string, args ...any) {
+	z.log(ctx, zapcore.WarnLevel, msg, nil, args...)
+}
+
+func (z *ZapAdapter) Error(ctx context.Context, msg string, err error, args ...any) {
+	z.log(ctx, zapcore.ErrorLevel, msg, err, args...)
+}
@@ -0,0 +1,91 @@
+package zap
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+	uberzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestZapAdapter(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	adapter := NewZapAdapter(uberzap.New(core))
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	adapter.Info(ctx, "hello world", "key", "value", "count", 3)
+	adapter.Debug(ctx, "debugging")
+	adapter.Warn(ctx, "warning")
+	adapter.Error(ctx, "oops", errors.New("mock error"), "user", "123")
+
+	entries := logs.All()
+	if len(entries) != 4 {
+		t.Fatalf("Expected 4 entries, got %d", len(entries))
+	}
+
+	info := entries[0]
+	if info.Message != "hello world" || info.Level != zapcore.InfoLevel {
+		t.Errorf("Unexpected entry: %+v", info.Entry)
+	}
+	fields := info.ContextMap()
+	if fields["key"] != "value" {
+		t.Errorf("Expected key 'value', got %v", fields["key"])
+	}
+	if fields["trace_id"] != sc.TraceID().String() || fields["span_id"] != sc.SpanID().String() {
+		t.Errorf("Expected trace correlation, got %v", fields)
+	}
+
+	wantLevels := []zapcore.Level{zapcore.InfoLevel, zapcore.DebugLevel, zapcore.WarnLevel, zapcore.ErrorLevel}
+	for i, e := range entries {
+		if e.Level != wantLevels[i] {
+			t.Errorf("Entry %d: expected level %v, got %v", i, wantLevels[i], e.Level)
+		}
+	}
+
+	errFields := entries[3].ContextMap()
+	if errFields["error"] != "mock error" {
+		t.Errorf("Expected error 'mock error', got %v", errFields["error"])
+	}
+}
+
+func TestZapAdapter_Sanitizer(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	sanitizer := func(key string, value any) any {
+		if key == "token" {
+			return "***"
+		}
+		return value
+	}
+	adapter := NewZapAdapter(uberzap.New(core), WithSanitizer(sanitizer))
+
+	adapter.Info(context.Background(), "login", "token", "secret123")
+	adapter.Debug(context.Background(), "dropped") // Below the core level
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	if got := entries[0].ContextMap()["token"]; got != "***" {
+		t.Errorf("Expected redacted token, got %v", got)
+	}
+}
+
+func TestZapAdapter_With(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger := NewZapAdapter(uberzap.New(core)).With("service", "billing")
+
+	logger.Info(context.Background(), "charged", "amount", 10)
+
+	fields := logs.All()[0].ContextMap()
+	if fields["service"] != "billing" || fields["amount"] != int64(10) {
+		t.Errorf("Expected bound and call fields, got %v", fields)
+	}
+}
@@ -0,0 +1,95 @@
+// Package zerolog provides an owl.Logger backed by github.com/rs/zerolog.
+package zerolog
+
+import (
+	"context"
+
+	"github.com/myuser/owl"
+	"github.com/myuser/owl/logs"
+	"github.com/rs/zerolog"
+)
+
+// ZerologAdapter implements owl.Logger using a zerolog.Logger.
+type ZerologAdapter struct {
+	logger    zerolog.Logger
+	sanitizer logs.Sanitizer
+}
+
+// NewZerologAdapter creates a new logger adapter.
+func NewZerologAdapter(l zerolog.Logger, opts ...func(*ZerologAdapter)) *ZerologAdapter {
+	z := &ZerologAdapter{logger: l}
+	for _, opt := range opts {
+		opt(z)
+	}
+	return z
+}
+
+// WithSanitizer sets the sanitizer hook.
+func WithSanitizer(fn logs.Sanitizer) func(*ZerologAdapter) {
+	return func(z *ZerologAdapter) {
+		z.sanitizer = fn
+	}
+}
+
+func (z *ZerologAdapter) log(ctx context.Context, ev *zerolog.Event, msg string, args ...any) {
+	if ev == nil {
+		// Level disabled
+		return
+	}
+	args = logs.SanitizeArgs(z.sanitizer, args)
+	addFields(ev, logs.ContextFields(ctx))
+	addFields(ev, args)
+	ev.Msg(msg)
+}
+
+// addFields converts key-value pairs into event fields.
+// A non-string key or a dangling value is reported under "!BADKEY", like slog does.
+func addFields(ev *zerolog.Event, args []any) {
+	for i := 0; i < len(args); i += 2 {
+		key, ok := args[i].(string)
+		if !ok || i+1 >= len(args) {
+			ev.Interface("!BADKEY", args[i])
+			i--
+			continue
+		}
+		ev.Interface(key, args[i+1])
+	}
+}
+
+// With returns an adapter whose entries carry the sanitized args.
+func (z *ZerologAdapter) With(args ...any) owl.Logger {
+	args = logs.SanitizeArgs(z.sanitizer, args)
+	zc := z.logger.With()
+	for i := 0; i < len(args); i += 2 {
+		key, ok := args[i].(string)
+		if !ok || i+1 >= len(args) {
+			zc = zc.Interface("!BADKEY", args[i])
+			i--
+			continue
+		}
+		zc = zc.Interface(key, args[i+1])
+	}
+	c := *z
+	c.logger = zc.Logger()
+	return &c
+}
+
+func (z *ZerologAdapter) Debug(ctx context.Context, msg string, args ...any) {
+	z.log(ctx, z.logger.Debug(), msg, args...)
+}
+
+func (z *ZerologAdapter) Info(ctx context.Context, msg string, args ...any) {
+	z.log(ctx, z.logger.Info(), msg, args...)
+}
+
+func (z *ZerologAdapter) Warn(ctx context.Context, msg string, args ...any) {
+	z.log(ctx, z.logger.Warn(), msg, args...)
+}
+
+func (z *ZerologAdapter) Error(ctx context.Context, msg string, err error, args ...any) {
+	ev := z.logger.Error()
+	if ev != nil && err != nil {
+		ev = ev.Err(err)
+	}
+	z.log(ctx, ev, msg, args...)
+}
@@ -0,0 +1,102 @@
+package zerolog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestZerologAdapter(t *testing.T) {
+	var buf bytes.Buffer
+	adapter := NewZerologAdapter(zerolog.New(&buf))
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+	m, _ := baggage.NewMember("tenant", "acme")
+	b, _ := baggage.New(m)
+	ctx = baggage.ContextWithBaggage(ctx, b)
+
+	t.Run("Info Log", func(t *testing.T) {
+		buf.Reset()
+		adapter.Info(ctx, "hello world", "key", "value")
+
+		var logEntry map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &logEntry); err != nil {
+			t.Fatalf("Failed to unmarshal log: %v", err)
+		}
+
+		if logEntry["message"] != "hello world" {
+			t.Errorf("Expected message 'hello world', got %v", logEntry["message"])
+		}
+		if logEntry["level"] != "info" {
+			t.Errorf("Expected level info, got %v", logEntry["level"])
+		}
+		if logEntry["key"] != "value" {
+			t.Errorf("Expected key 'value', got %v", logEntry["key"])
+		}
+		if logEntry["trace_id"] != sc.TraceID().String() {
+			t.Errorf("Expected trace_id %s, got %v", sc.TraceID(), logEntry["trace_id"])
+		}
+		if logEntry["tenant"] != "acme" {
+			t.Errorf("Expected baggage tenant 'acme', got %v", logEntry["tenant"])
+		}
+	})
+
+	t.Run("Error Log", func(t *testing.T) {
+		buf.Reset()
+		adapter.Error(ctx, "oops", errors.New("mock error"))
+
+		var logEntry map[string]any
+		json.Unmarshal(buf.Bytes(), &logEntry)
+
+		if logEntry["level"] != "error" {
+			t.Errorf("Expected level error, got %v", logEntry["level"])
+		}
+		if logEntry["error"] != "mock error" {
+			t.Errorf("Expected error 'mock error', got %v", logEntry["error"])
+		}
+	})
+}
+
+func TestZerologAdapter_Sanitizer(t *testing.T) {
+	var buf bytes.Buffer
+	sanitizer := func(key string, value any) any {
+		if key == "token" {
+			return "***"
+		}
+		return value
+	}
+	adapter := NewZerologAdapter(zerolog.New(&buf), WithSanitizer(sanitizer))
+
+	adapter.Info(context.Background(), "login", "token", "secret123")
+
+	var logEntry map[string]any
+	json.Unmarshal(buf.Bytes(), &logEntry)
+
+	if logEntry["token"] != "***" {
+		t.Errorf("Expected redacted token, got %v", logEntry["token"])
+	}
+}
+
+func TestZerologAdapter_With(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewZerologAdapter(zerolog.New(&buf)).With("service", "billing")
+
+	logger.Info(context.Background(), "charged", "amount", 10)
+
+	var logEntry map[string]any
+	json.Unmarshal(buf.Bytes(), &logEntry)
+
+	if logEntry["service"] != "billing" || logEntry["amount"] != float64(10) {
+		t.Errorf("Expected bound and call fields, got %v", logEntry)
+	}
+}
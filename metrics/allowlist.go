@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+
+	"github.com/myuser/owl"
+)
+
+// WithAttributeAllowlist makes the adapter drop every attribute whose key is
+// not in keys, protecting the backend from high-cardinality values such as
+// user IDs. See AllowAttributes.
+func WithAttributeAllowlist(keys ...string) func(*OTelAdapter) {
+	return func(o *OTelAdapter) {
+		o.allowlist = keys
+	}
+}
+
+// AllowAttributes wraps m so that attributes whose key is not in keys are
+// removed before reaching the instrument. The first time a disallowed key is
+// seen on a metric, a warning is logged through owl.GetLogger().
+func AllowAttributes(m owl.Monitor, keys ...string) owl.Monitor {
+	f := &attributeFilter{
+		next:    m,
+		allowed: make(map[string]struct{}, len(keys)),
+	}
+	for _, k := range keys {
+		f.allowed[k] = struct{}{}
+	}
+	return f
+}
+
+// attributeFilter is an owl.Monitor that strips disallowed attributes.
+type attributeFilter struct {
+	next    owl.Monitor
+	allowed map[string]struct{}
+	warned  sync.Map // "metric\x00key" -> struct{}
+}
+
+// filter returns attrs without disallowed keys. attrs is returned as is when
+// every key is allowed, so the common case does not allocate.
+func (f *attributeFilter) filter(ctx context.Context, metric string, attrs []owl.Attribute) []owl.Attribute {
+	for i, a := range attrs {
+		if _, ok := f.allowed[a.Key]; ok {
+			continue
+		}
+		res := append([]owl.Attribute(nil), attrs[:i]...)
+		for _, a := range attrs[i:] {
+			if _, ok := f.allowed[a.Key]; ok {
+				res = append(res, a)
+			} else {
+				f.warn(ctx, metric, a.Key)
+			}
+		}
+		return res
+	}
+	return attrs
+}
+
+func (f *attributeFilter) warn(ctx context.Context, metric, key string) {
+	if _, seen := f.warned.LoadOrStore(metric+"\x00"+key, struct{}{}); !seen {
+		owl.GetLogger().Warn(ctx, "metric_attribute_dropped", "metric", metric, "key", key)
+	}
+}
+
+func (f *attributeFilter) Counter(name string, opts ...owl.MetricOption) owl.Counter {
+	return &filteredCounter{f: f, name: name, next: f.next.Counter(name, opts...)}
+}
+
+func (f *attributeFilter) Histogram(name string, opts ...owl.MetricOption) owl.Histogram {
+	return &filteredHistogram{f: f, name: name, next: f.next.Histogram(name, opts...)}
+}
+
+func (f *attributeFilter) Gauge(name string, opts ...owl.MetricOption) owl.Gauge {
+	return &filteredGauge{f: f, name: name, next: f.next.Gauge(name, opts...)}
+}
+
+func (f *attributeFilter) UpDownCounter(name string, opts ...owl.MetricOption) owl.UpDownCounter {
+	return &filteredUpDownCounter{f: f, name: name, next: f.next.UpDownCounter(name, opts...)}
+}
+
+func (f *attributeFilter) ObservableGauge(name string, callback func(ctx context.Context) float64, opts ...owl.MetricOption) {
+	f.next.ObservableGauge(name, callback, opts...)
+}
+
+// Wrappers
+
+type filteredCounter struct {
+	f    *attributeFilter
+	name string
+	next owl.Counter
+}
+
+func (c *filteredCounter) Inc(ctx context.Context, attrs ...owl.Attribute) {
+	c.next.Inc(ctx, c.f.filter(ctx, c.name, attrs)...)
+}
+
+func (c *filteredCounter) Add(ctx context.Context, delta float64, attrs ...owl.Attribute) {
+	c.next.Add(ctx, delta, c.f.filter(ctx, c.name, attrs)...)
+}
+
+type filteredHistogram struct {
+	f    *attributeFilter
+	name string
+	next owl.Histogram
+}
+
+func (h *filteredHistogram) Record(ctx context.Context, value float64, attrs ...owl.Attribute) {
+	h.next.Record(ctx, value, h.f.filter(ctx, h.name, attrs)...)
+}
+
+type filteredGauge struct {
+	f    *attributeFilter
+	name string
+	next owl.Gauge
+}
+
+func (g *filteredGauge) Set(ctx context.Context, value float64, attrs ...owl.Attribute) {
+	g.next.Set(ctx, value, g.f.filter(ctx, g.name, attrs)...)
+}
+
+type filteredUpDownCounter struct {
+	f    *attributeFilter
+	name string
+	next owl.UpDownCounter
+}
+
+func (c *filteredUpDownCounter) Add(ctx context.Context, delta float64, attrs ...owl.Attribute) {
+	c.next.Add(ctx, delta, c.f.filter(ctx, c.name, attrs)...)
+}
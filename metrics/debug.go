@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/myuser/owl"
+)
+
+// DebugHandler serves a plain-text dump of the current values of m, one series
+// per line, e.g. in-flight requests and latency summaries while developing
+// locally. m must be an *InMemory (or another monitor with a Snapshot method);
+// for any other monitor the handler responds 501 Not Implemented.
+func DebugHandler(m owl.Monitor) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		src, ok := m.(interface{ Snapshot() Snapshot })
+		if !ok {
+			http.Error(w, "monitor does not support snapshots, use metrics.NewInMemory", http.StatusNotImplemented)
+			return
+		}
+		s := src.Snapshot()
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintln(w, "# counters")
+		for _, k := range sortedKeys(s.Counters) {
+			fmt.Fprintf(w, "%s %s\n", k, formatFloat(s.Counters[k]))
+		}
+		fmt.Fprintln(w, "# gauges")
+		for _, k := range sortedKeys(s.Gauges) {
+			fmt.Fprintf(w, "%s %s\n", k, formatFloat(s.Gauges[k]))
+		}
+		fmt.Fprintln(w, "# histograms")
+		for _, k := range sortedKeys(s.Histograms) {
+			h := s.Histograms[k]
+			fmt.Fprintf(w, "%s count=%d sum=%s min=%s max=%s mean=%s\n", k, h.Count,
+				formatFloat(h.Sum), formatFloat(h.Min), formatFloat(h.Max), formatFloat(h.Mean()))
+		}
+	})
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
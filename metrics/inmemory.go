@@ -0,0 +1,190 @@
+package metrics
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/myuser/owl"
+)
+
+// InMemory is an owl.Monitor that keeps every series in memory, for local
+// development without a metrics backend. Serve it with DebugHandler or read it
+// with Snapshot. It never evicts series, so don't use it in production.
+type InMemory struct {
+	mu          sync.Mutex
+	counters    map[string]float64
+	gauges      map[string]float64 // UpDownCounters accumulate here too
+	histograms  map[string]*HistogramSummary
+	observables map[string]func(ctx context.Context) float64
+}
+
+// HistogramSummary aggregates the samples recorded on one histogram series.
+type HistogramSummary struct {
+	Count uint64
+	Sum   float64
+	Min   float64
+	Max   float64
+}
+
+// Mean returns the average sample, or 0 if none were recorded.
+func (h HistogramSummary) Mean() float64 {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / float64(h.Count)
+}
+
+// Snapshot is a point-in-time copy of an InMemory monitor. Maps are keyed by
+// series, formatted as name{key="value",...} with keys sorted, or just name
+// when there are no attributes.
+type Snapshot struct {
+	Counters   map[string]float64
+	Gauges     map[string]float64 // Includes up-down counters and observable gauges
+	Histograms map[string]HistogramSummary
+}
+
+// NewInMemory creates an empty in-memory monitor.
+func NewInMemory() *InMemory {
+	return &InMemory{
+		counters:    make(map[string]float64),
+		gauges:      make(map[string]float64),
+		histograms:  make(map[string]*HistogramSummary),
+		observables: make(map[string]func(ctx context.Context) float64),
+	}
+}
+
+// Snapshot copies the current values. Observable gauge callbacks are invoked.
+func (m *InMemory) Snapshot() Snapshot {
+	m.mu.Lock()
+	s := Snapshot{
+		Counters:   make(map[string]float64, len(m.counters)),
+		Gauges:     make(map[string]float64, len(m.gauges)+len(m.observables)),
+		Histograms: make(map[string]HistogramSummary, len(m.histograms)),
+	}
+	for k, v := range m.counters {
+		s.Counters[k] = v
+	}
+	for k, v := range m.gauges {
+		s.Gauges[k] = v
+	}
+	for k, v := range m.histograms {
+		s.Histograms[k] = *v
+	}
+	observables := make(map[string]func(ctx context.Context) float64, len(m.observables))
+	for k, v := range m.observables {
+		observables[k] = v
+	}
+	m.mu.Unlock()
+
+	// Callbacks run unlocked, as they may record metrics themselves
+	for name, callback := range observables {
+		s.Gauges[name] = callback(context.Background())
+	}
+	return s
+}
+
+func (m *InMemory) Counter(name string, opts ...owl.MetricOption) owl.Counter {
+	return &inMemoryCounter{name: name, m: m}
+}
+
+func (m *InMemory) Histogram(name string, opts ...owl.MetricOption) owl.Histogram {
+	return &inMemoryHistogram{name: name, m: m}
+}
+
+func (m *InMemory) Gauge(name string, opts ...owl.MetricOption) owl.Gauge {
+	return &inMemoryGauge{name: name, m: m}
+}
+
+func (m *InMemory) UpDownCounter(name string, opts ...owl.MetricOption) owl.UpDownCounter {
+	return &inMemoryGauge{name: name, m: m}
+}
+
+// ObservableGauge registers callback, which is invoked on every Snapshot.
+func (m *InMemory) ObservableGauge(name string, callback func(ctx context.Context) float64, opts ...owl.MetricOption) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.observables[name] = callback
+}
+
+// seriesKey formats name and attrs as name{key="value",...} with sorted keys.
+func seriesKey(name string, attrs []owl.Attribute) string {
+	if len(attrs) == 0 {
+		return name
+	}
+	sorted := append([]owl.Attribute(nil), attrs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Key < sorted[j].Key })
+
+	var b strings.Builder
+	b.WriteString(name)
+	b.WriteByte('{')
+	for i, a := range sorted {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(a.Key)
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(a.Value))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+type inMemoryCounter struct {
+	name string
+	m    *InMemory
+}
+
+func (c *inMemoryCounter) Inc(ctx context.Context, attrs ...owl.Attribute) {
+	c.Add(ctx, 1, attrs...)
+}
+
+func (c *inMemoryCounter) Add(ctx context.Context, delta float64, attrs ...owl.Attribute) {
+	key := seriesKey(c.name, attrs)
+	c.m.mu.Lock()
+	defer c.m.mu.Unlock()
+	c.m.counters[key] += delta
+}
+
+type inMemoryHistogram struct {
+	name string
+	m    *InMemory
+}
+
+func (h *inMemoryHistogram) Record(ctx context.Context, value float64, attrs ...owl.Attribute) {
+	key := seriesKey(h.name, attrs)
+	h.m.mu.Lock()
+	defer h.m.mu.Unlock()
+	s, ok := h.m.histograms[key]
+	if !ok {
+		s = &HistogramSummary{Min: math.Inf(1), Max: math.Inf(-1)}
+		h.m.histograms[key] = s
+	}
+	s.Count++
+	s.Sum += value
+	s.Min = math.Min(s.Min, value)
+	s.Max = math.Max(s.Max, value)
+}
+
+// inMemoryGauge backs both Gauge and UpDownCounter.
+type inMemoryGauge struct {
+	name string
+	m    *InMemory
+}
+
+func (g *inMemoryGauge) Set(ctx context.Context, value float64, attrs ...owl.Attribute) {
+	key := seriesKey(g.name, attrs)
+	g.m.mu.Lock()
+	defer g.m.mu.Unlock()
+	g.m.gauges[key] = value
+}
+
+func (g *inMemoryGauge) Add(ctx context.Context, delta float64, attrs ...owl.Attribute) {
+	key := seriesKey(g.name, attrs)
+	g.m.mu.Lock()
+	defer g.m.mu.Unlock()
+	g.m.gauges[key] += delta
+}
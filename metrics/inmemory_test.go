@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/myuser/owl"
+)
+
+func TestInMemory_Snapshot(t *testing.T) {
+	ctx := context.Background()
+	m := NewInMemory()
+
+	m.Counter("jobs_total").Inc(ctx, owl.Attr("status", "ok"), owl.Attr("queue", "mail"))
+	m.Counter("jobs_total").Add(ctx, 2, owl.Attr("queue", "mail"), owl.Attr("status", "ok"))
+	m.UpDownCounter("in_flight").Add(ctx, 3)
+	m.UpDownCounter("in_flight").Add(ctx, -1)
+	m.ObservableGauge("queue_depth", func(ctx context.Context) float64 { return 7 })
+	for _, v := range []float64{0.1, 0.5, 0.3} {
+		m.Histogram("latency_seconds").Record(ctx, v)
+	}
+
+	s := m.Snapshot()
+	if got := s.Counters[`jobs_total{queue="mail",status="ok"}`]; got != 3 {
+		t.Errorf("Expected counter 3 regardless of attribute order, got %v (%v)", got, s.Counters)
+	}
+	if s.Gauges["in_flight"] != 2 || s.Gauges["queue_depth"] != 7 {
+		t.Errorf("Unexpected gauges %v", s.Gauges)
+	}
+	h := s.Histograms["latency_seconds"]
+	if h.Count != 3 || h.Min != 0.1 || h.Max != 0.5 {
+		t.Errorf("Unexpected histogram summary %+v", h)
+	}
+}
+
+func TestDebugHandler(t *testing.T) {
+	m := NewInMemory()
+	m.Counter("http_requests_total").Inc(context.Background(), owl.Attr("status", "200"))
+	m.Histogram("http_request_duration_seconds").Record(context.Background(), 0.25)
+
+	w := httptest.NewRecorder()
+	DebugHandler(m).ServeHTTP(w, httptest.NewRequest("GET", "/debug/metrics", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, `http_requests_total{status="200"} 1`+"\n") {
+		t.Errorf("Expected counter line, got:\n%s", body)
+	}
+	if !strings.Contains(body, "http_request_duration_seconds count=1 sum=0.25") {
+		t.Errorf("Expected histogram summary line, got:\n%s", body)
+	}
+
+	w = httptest.NewRecorder()
+	DebugHandler(owl.NoOpMonitor{}).ServeHTTP(w, httptest.NewRequest("GET", "/debug/metrics", nil))
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("Expected 501 for a monitor without snapshots, got %d", w.Code)
+	}
+}
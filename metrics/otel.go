@@ -2,6 +2,8 @@ package metrics
 
 import (
 	"context"
+	"math"
+	"sync"
 
 	"github.com/myuser/owl"
 	"go.opentelemetry.io/otel/attribute"
@@ -10,34 +12,222 @@ import (
 
 // OTelAdapter implements owl.Monitor using OpenTelemetry.
 type OTelAdapter struct {
-	meter metric.Meter
+	meter     metric.Meter
+	allowlist []string // Set by WithAttributeAllowlist
+
+	// instruments caches wrapped instruments by name (as cachedInstrument), so
+	// hot paths calling Counter/Histogram repeatedly don't re-create them, and
+	// a name is never registered as two OTel instruments of different types.
+	instruments sync.Map
+}
+
+// cachedInstrument is a wrapped instrument with the type it was created as.
+type cachedInstrument struct {
+	kind  string
+	int64 bool
+	inst  any
+}
+
+// resolve parses opts, skipping the work when there are none.
+func resolve(opts []owl.MetricOption) owl.MetricConfig {
+	if len(opts) == 0 {
+		return owl.MetricConfig{}
+	}
+	return owl.NewMetricConfig(opts...)
+}
+
+// lookup returns the cached instrument for name. The first request for a name
+// defines its instrument and options; a later request for another kind, or
+// with and without owl.WithInt64, is logged through owl.GetLogger(), and
+// returns nil when the kinds differ, for the caller to fall back to a no-op.
+func (o *OTelAdapter) lookup(kind, name string, int64 bool) (any, bool) {
+	v, ok := o.instruments.Load(name)
+	if !ok {
+		return nil, false
+	}
+	return o.check(v.(cachedInstrument), kind, name, int64), true
+}
+
+// store caches inst for name unless another goroutine got there first, and
+// returns the instrument to use as lookup does.
+func (o *OTelAdapter) store(kind, name string, int64 bool, inst any) any {
+	v, _ := o.instruments.LoadOrStore(name, cachedInstrument{kind: kind, int64: int64, inst: inst})
+	return o.check(v.(cachedInstrument), kind, name, int64)
+}
+
+func (o *OTelAdapter) check(c cachedInstrument, kind, name string, int64 bool) any {
+	if c.kind == kind && c.int64 == int64 {
+		return c.inst
+	}
+	owl.GetLogger().Error(context.Background(), "otel_instrument_conflict", nil,
+		"metric", name,
+		"instrument", c.kind, "int64", c.int64,
+		"requested", kind, "requested_int64", int64,
+	)
+	if c.kind != kind {
+		return nil
+	}
+	return c.inst
+}
+
+// as returns inst as a T, or noop when lookup or store returned nil.
+func as[T any](inst any, noop T) T {
+	if t, ok := inst.(T); ok {
+		return t
+	}
+	return noop
+}
+
+// instrumentOptions converts the description and unit of cfg into OTel options.
+func instrumentOptions(cfg owl.MetricConfig) []metric.InstrumentOption {
+	var opts []metric.InstrumentOption
+	if cfg.Description != "" {
+		opts = append(opts, metric.WithDescription(cfg.Description))
+	}
+	if cfg.Unit != "" {
+		opts = append(opts, metric.WithUnit(cfg.Unit))
+	}
+	return opts
 }
 
 // NewOTelAdapter initializes an adapter with an existing OTel Meter.
 // The Application Logic (main.go) is responsible for setting up the Exporter (Prometheus/OTLP)
 // and the MeterProvider.
-func NewOTelAdapter(meter metric.Meter) owl.Monitor {
-	return &OTelAdapter{
+func NewOTelAdapter(meter metric.Meter, opts ...func(*OTelAdapter)) owl.Monitor {
+	o := &OTelAdapter{
 		meter: meter,
 	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.allowlist != nil {
+		return AllowAttributes(o, o.allowlist...)
+	}
+	return o
 }
 
+// Counter returns a Float64Counter, or an Int64Counter with owl.WithInt64.
 func (o *OTelAdapter) Counter(name string, opts ...owl.MetricOption) owl.Counter {
-	// In a real impl, we'd parse opts to set description/units/tags
-	c, err := o.meter.Float64Counter(name)
+	cfg := resolve(opts)
+	if c, ok := o.lookup("counter", name, cfg.Int64); ok {
+		return as(c, owl.Counter(&otelCounter{}))
+	}
+	if cfg.Int64 {
+		return o.int64Counter(name, cfg)
+	}
+
+	var counterOpts []metric.Float64CounterOption
+	for _, opt := range instrumentOptions(cfg) {
+		counterOpts = append(counterOpts, opt)
+	}
+	c, err := o.meter.Float64Counter(name, counterOpts...)
 	if err != nil {
-		// Fallback to nil internal counter (safe due to checks below)
+		// Fallback to nil internal counter (safe due to checks below).
+		// Not cached, so a later call can retry.
 		return &otelCounter{c: nil}
 	}
-	return &otelCounter{c: c}
+	return as(o.store("counter", name, false, &otelCounter{c: c}), owl.Counter(&otelCounter{}))
 }
 
+func (o *OTelAdapter) int64Counter(name string, cfg owl.MetricConfig) owl.Counter {
+	var counterOpts []metric.Int64CounterOption
+	for _, opt := range instrumentOptions(cfg) {
+		counterOpts = append(counterOpts, opt)
+	}
+	c, err := o.meter.Int64Counter(name, counterOpts...)
+	if err != nil {
+		return &otelInt64Counter{c: nil}
+	}
+	return as(o.store("counter", name, true, &otelInt64Counter{c: c}), owl.Counter(&otelCounter{}))
+}
+
+// Histogram returns a Float64Histogram, or an Int64Histogram with owl.WithInt64.
 func (o *OTelAdapter) Histogram(name string, opts ...owl.MetricOption) owl.Histogram {
-	h, err := o.meter.Float64Histogram(name)
+	cfg := resolve(opts)
+	if h, ok := o.lookup("histogram", name, cfg.Int64); ok {
+		return as(h, owl.Histogram(&otelHistogram{}))
+	}
+	if cfg.Int64 {
+		return o.int64Histogram(name, cfg)
+	}
+
+	var histOpts []metric.Float64HistogramOption
+	for _, opt := range instrumentOptions(cfg) {
+		histOpts = append(histOpts, opt)
+	}
+	if len(cfg.Buckets) > 0 {
+		histOpts = append(histOpts, metric.WithExplicitBucketBoundaries(cfg.Buckets...))
+	}
+	h, err := o.meter.Float64Histogram(name, histOpts...)
 	if err != nil {
 		return &otelHistogram{h: nil}
 	}
-	return &otelHistogram{h: h}
+	return as(o.store("histogram", name, false, &otelHistogram{h: h}), owl.Histogram(&otelHistogram{}))
+}
+
+func (o *OTelAdapter) int64Histogram(name string, cfg owl.MetricConfig) owl.Histogram {
+	var histOpts []metric.Int64HistogramOption
+	for _, opt := range instrumentOptions(cfg) {
+		histOpts = append(histOpts, opt)
+	}
+	if len(cfg.Buckets) > 0 {
+		histOpts = append(histOpts, metric.WithExplicitBucketBoundaries(cfg.Buckets...))
+	}
+	h, err := o.meter.Int64Histogram(name, histOpts...)
+	if err != nil {
+		return &otelInt64Histogram{h: nil}
+	}
+	return as(o.store("histogram", name, true, &otelInt64Histogram{h: h}), owl.Histogram(&otelHistogram{}))
+}
+
+func (o *OTelAdapter) Gauge(name string, opts ...owl.MetricOption) owl.Gauge {
+	cfg := resolve(opts)
+	if g, ok := o.lookup("gauge", name, false); ok {
+		return as(g, &otelGauge{})
+	}
+
+	var gaugeOpts []metric.Float64GaugeOption
+	for _, opt := range instrumentOptions(cfg) {
+		gaugeOpts = append(gaugeOpts, opt)
+	}
+	g, err := o.meter.Float64Gauge(name, gaugeOpts...)
+	if err != nil {
+		return &otelGauge{g: nil}
+	}
+	return as(o.store("gauge", name, false, &otelGauge{g: g}), &otelGauge{})
+}
+
+func (o *OTelAdapter) UpDownCounter(name string, opts ...owl.MetricOption) owl.UpDownCounter {
+	cfg := resolve(opts)
+	if c, ok := o.lookup("updowncounter", name, false); ok {
+		return as(c, &otelUpDownCounter{})
+	}
+
+	var udOpts []metric.Float64UpDownCounterOption
+	for _, opt := range instrumentOptions(cfg) {
+		udOpts = append(udOpts, opt)
+	}
+	c, err := o.meter.Float64UpDownCounter(name, udOpts...)
+	if err != nil {
+		return &otelUpDownCounter{c: nil}
+	}
+	return as(o.store("updowncounter", name, false, &otelUpDownCounter{c: c}), &otelUpDownCounter{})
+}
+
+// ObservableGauge registers a Float64ObservableGauge reporting callback on every collection.
+// Registration errors are dropped, like instrument creation errors.
+func (o *OTelAdapter) ObservableGauge(name string, callback func(ctx context.Context) float64, opts ...owl.MetricOption) {
+	cfg := owl.NewMetricConfig(opts...)
+	gaugeOpts := []metric.Float64ObservableGaugeOption{
+		metric.WithFloat64Callback(func(ctx context.Context, obs metric.Float64Observer) error {
+			obs.Observe(callback(ctx))
+			return nil
+		}),
+	}
+	for _, opt := range instrumentOptions(cfg) {
+		gaugeOpts = append(gaugeOpts, opt)
+	}
+	_, _ = o.meter.Float64ObservableGauge(name, gaugeOpts...)
 }
 
 // Wrappers
@@ -68,6 +258,54 @@ func (h *otelHistogram) Record(ctx context.Context, value float64, attrs ...owl.
 	}
 }
 
+// otelInt64Counter rounds deltas to the nearest integer.
+type otelInt64Counter struct {
+	c metric.Int64Counter
+}
+
+func (c *otelInt64Counter) Inc(ctx context.Context, attrs ...owl.Attribute) {
+	if c.c != nil {
+		c.c.Add(ctx, 1, metric.WithAttributes(toOtelAttrs(attrs)...))
+	}
+}
+
+func (c *otelInt64Counter) Add(ctx context.Context, delta float64, attrs ...owl.Attribute) {
+	if c.c != nil {
+		c.c.Add(ctx, int64(math.Round(delta)), metric.WithAttributes(toOtelAttrs(attrs)...))
+	}
+}
+
+// otelInt64Histogram rounds values to the nearest integer.
+type otelInt64Histogram struct {
+	h metric.Int64Histogram
+}
+
+func (h *otelInt64Histogram) Record(ctx context.Context, value float64, attrs ...owl.Attribute) {
+	if h.h != nil {
+		h.h.Record(ctx, int64(math.Round(value)), metric.WithAttributes(toOtelAttrs(attrs)...))
+	}
+}
+
+type otelGauge struct {
+	g metric.Float64Gauge
+}
+
+func (g *otelGauge) Set(ctx context.Context, value float64, attrs ...owl.Attribute) {
+	if g.g != nil {
+		g.g.Record(ctx, value, metric.WithAttributes(toOtelAttrs(attrs)...))
+	}
+}
+
+type otelUpDownCounter struct {
+	c metric.Float64UpDownCounter
+}
+
+func (c *otelUpDownCounter) Add(ctx context.Context, delta float64, attrs ...owl.Attribute) {
+	if c.c != nil {
+		c.c.Add(ctx, delta, metric.WithAttributes(toOtelAttrs(attrs)...))
+	}
+}
+
 // Helper to convert attributes
 func toOtelAttrs(attrs []owl.Attribute) []attribute.KeyValue {
 	if len(attrs) == 0 {
@@ -5,7 +5,10 @@ import (
 	"testing"
 
 	"github.com/myuser/owl"
+	"github.com/myuser/owl/owltest"
 	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 )
 
 func TestOTelAdapter(t *testing.T) {
@@ -28,4 +31,196 @@ func TestOTelAdapter(t *testing.T) {
 		histo := adapter.Histogram("test_histogram")
 		histo.Record(ctx, 100, owl.Attr("key", "val"))
 	})
+
+	t.Run("Gauge", func(t *testing.T) {
+		gauge := adapter.Gauge("test_gauge")
+		gauge.Set(ctx, 42, owl.Attr("key", "val"))
+	})
+
+	t.Run("UpDownCounter", func(t *testing.T) {
+		c := adapter.UpDownCounter("test_updown")
+		c.Add(ctx, 1, owl.Attr("key", "val"))
+		c.Add(ctx, -1, owl.Attr("key", "val"))
+	})
+}
+
+func TestOTelAdapter_CachesInstruments(t *testing.T) {
+	adapter := NewOTelAdapter(metric.NewMeterProvider().Meter("test"))
+
+	if adapter.Counter("c") != adapter.Counter("c") {
+		t.Error("Expected the same counter for the same name")
+	}
+	if adapter.Histogram("h") != adapter.Histogram("h") {
+		t.Error("Expected the same histogram for the same name")
+	}
+	if adapter.Counter("c") == adapter.Counter("other") {
+		t.Error("Expected distinct counters for distinct names")
+	}
+}
+
+func TestOTelAdapter_InstrumentConflict(t *testing.T) {
+	logger := owltest.NewLogger()
+	owl.SetLogger(logger)
+	defer owl.SetLogger(owl.NoOpLogger{})
+	adapter := NewOTelAdapter(metric.NewMeterProvider().Meter("test"))
+	ctx := context.Background()
+
+	counter := adapter.Counter("requests_total")
+	if adapter.Counter("requests_total", owl.WithInt64()) != counter {
+		t.Error("Expected the first instrument for a name regardless of WithInt64")
+	}
+	h := adapter.Histogram("requests_total")
+	h.Record(ctx, 1) // A no-op, must not panic
+	if h == adapter.Histogram("other") {
+		t.Error("Expected a no-op histogram for a counter's name")
+	}
+	if n := logger.CountLevel("ERROR"); n != 2 {
+		t.Errorf("Expected each conflict logged, got %d errors", n)
+	}
+	if v, _ := logger.Find("otel_instrument_conflict").Field("metric"); v != "requests_total" {
+		t.Errorf("Expected the metric name logged, got %v", logger.Entries)
+	}
+}
+
+func BenchmarkOTelAdapter_Counter(b *testing.B) {
+	adapter := NewOTelAdapter(metric.NewMeterProvider().Meter("bench"))
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		adapter.Counter("requests_total").Inc(ctx)
+	}
+}
+
+func TestOTelAdapter_MetricOptions(t *testing.T) {
+	reader := metric.NewManualReader()
+	adapter := NewOTelAdapter(metric.NewMeterProvider(metric.WithReader(reader)).Meter("test"))
+	ctx := context.Background()
+
+	adapter.Counter("jobs_total", owl.WithDescription("Processed jobs")).Inc(ctx)
+	adapter.Histogram("job_duration_seconds",
+		owl.WithDescription("Job duration"),
+		owl.WithUnit("s"),
+		owl.WithBuckets([]float64{0.1, 1}),
+	).Record(ctx, 0.5)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	found := map[string]metricdata.Metrics{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			found[m.Name] = m
+		}
+	}
+
+	if got := found["jobs_total"].Description; got != "Processed jobs" {
+		t.Errorf("Counter description = %q", got)
+	}
+	hist := found["job_duration_seconds"]
+	if hist.Description != "Job duration" || hist.Unit != "s" {
+		t.Errorf("Histogram metadata = %q / %q", hist.Description, hist.Unit)
+	}
+	data, ok := hist.Data.(metricdata.Histogram[float64])
+	if !ok || len(data.DataPoints) != 1 {
+		t.Fatalf("Unexpected histogram data: %#v", hist.Data)
+	}
+	if bounds := data.DataPoints[0].Bounds; len(bounds) != 2 || bounds[1] != 1 {
+		t.Errorf("Expected bucket bounds [0.1 1], got %v", bounds)
+	}
+}
+
+func TestOTelAdapter_Int64(t *testing.T) {
+	reader := metric.NewManualReader()
+	adapter := NewOTelAdapter(metric.NewMeterProvider(metric.WithReader(reader)).Meter("test"))
+	ctx := context.Background()
+
+	counter := adapter.Counter("requests_total", owl.WithInt64())
+	counter.Inc(ctx)
+	counter.Add(ctx, 2)
+	adapter.Histogram("response_size_bytes", owl.WithInt64(), owl.WithUnit("By")).Record(ctx, 1023.6)
+
+	if adapter.Counter("requests_total", owl.WithInt64()) != counter {
+		t.Error("Expected the int instrument to be cached")
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	found := map[string]metricdata.Metrics{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			found[m.Name] = m
+		}
+	}
+
+	sum, ok := found["requests_total"].Data.(metricdata.Sum[int64])
+	if !ok || len(sum.DataPoints) != 1 || sum.DataPoints[0].Value != 3 {
+		t.Errorf("Expected int64 sum of 3, got %#v", found["requests_total"].Data)
+	}
+	hist, ok := found["response_size_bytes"].Data.(metricdata.Histogram[int64])
+	if !ok || len(hist.DataPoints) != 1 || hist.DataPoints[0].Sum != 1024 {
+		t.Errorf("Expected int64 histogram with rounded sum 1024, got %#v", found["response_size_bytes"].Data)
+	}
+}
+
+func TestOTelAdapter_ObservableGauge(t *testing.T) {
+	reader := metric.NewManualReader()
+	adapter := NewOTelAdapter(metric.NewMeterProvider(metric.WithReader(reader)).Meter("test"))
+	ctx := context.Background()
+
+	adapter.ObservableGauge("pool_connections", func(ctx context.Context) float64 {
+		return 7
+	}, owl.WithDescription("Open pool connections"))
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if len(rm.ScopeMetrics) != 1 || len(rm.ScopeMetrics[0].Metrics) != 1 {
+		t.Fatalf("Expected 1 metric, got %#v", rm.ScopeMetrics)
+	}
+	m := rm.ScopeMetrics[0].Metrics[0]
+	gauge, ok := m.Data.(metricdata.Gauge[float64])
+	if m.Name != "pool_connections" || !ok || len(gauge.DataPoints) != 1 || gauge.DataPoints[0].Value != 7 {
+		t.Errorf("Expected observed value 7, got %#v", m)
+	}
+}
+
+func TestOTelAdapter_AttributeAllowlist(t *testing.T) {
+	logger := owltest.NewLogger()
+	owl.SetLogger(logger)
+	defer owl.SetLogger(owl.NoOpLogger{})
+
+	reader := metric.NewManualReader()
+	adapter := NewOTelAdapter(metric.NewMeterProvider(metric.WithReader(reader)).Meter("test"),
+		WithAttributeAllowlist("method", "status"))
+	ctx := context.Background()
+
+	counter := adapter.Counter("requests_total")
+	counter.Inc(ctx, owl.Attr("method", "GET"), owl.Attr("user_id", "u-1"))
+	counter.Inc(ctx, owl.Attr("method", "GET"), owl.Attr("user_id", "u-2"))
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	sum := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[float64])
+	if len(sum.DataPoints) != 1 || sum.DataPoints[0].Value != 2 {
+		t.Fatalf("Expected one aggregated series with value 2, got %#v", sum.DataPoints)
+	}
+	dp := sum.DataPoints[0]
+	if _, ok := dp.Attributes.Value("user_id"); ok {
+		t.Error("Expected user_id to be stripped")
+	}
+	if v, _ := dp.Attributes.Value("method"); v.AsString() != "GET" {
+		t.Errorf("Expected allowed method attribute, got %v", v)
+	}
+	if n := logger.CountLevel("WARN"); n != 1 {
+		t.Errorf("Expected a single warning for the dropped key, got %d", n)
+	}
 }
@@ -0,0 +1,262 @@
+// Package prometheus provides an owl.Monitor backed by a Prometheus registry.
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/myuser/owl"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PrometheusAdapter implements owl.Monitor using Prometheus collectors.
+//
+// Collectors are created lazily on first use, with the description of the
+// MetricOptions as help text and their buckets for histograms (the unit is
+// ignored; encode it in the name as Prometheus recommends). Label names are taken
+// from the attribute keys of that first call. Prometheus requires a fixed label
+// set per metric, so later calls with different attribute keys are dropped; the
+// first drop of each metric is logged through owl.GetLogger().
+type PrometheusAdapter struct {
+	registry  *prom.Registry
+	exemplars bool // Attach trace_id exemplars to histogram observations
+
+	// Vecs by metric name, read without locking on every observation; mu
+	// only serializes their creation and registration.
+	mu         sync.Mutex
+	counters   sync.Map // *prom.CounterVec
+	histograms sync.Map // *prom.HistogramVec
+	gauges     sync.Map // *prom.GaugeVec, backing both Gauge and UpDownCounter
+
+	dropped sync.Map // Names of metrics with a logged drop, see logDropped
+}
+
+// NewPrometheusAdapter creates an adapter registering its collectors on reg.
+// If reg is nil, a new registry is created (see Registry).
+func NewPrometheusAdapter(reg *prom.Registry, opts ...func(*PrometheusAdapter)) *PrometheusAdapter {
+	if reg == nil {
+		reg = prom.NewRegistry()
+	}
+	p := &PrometheusAdapter{registry: reg}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// WithExemplars attaches the trace_id of the sampled span in the context as an
+// exemplar to histogram observations, linking latency buckets to example traces.
+// Exemplars are only exposed in the OpenMetrics format, so serve the registry
+// with promhttp.HandlerOpts{EnableOpenMetrics: true}.
+func WithExemplars(enabled bool) func(*PrometheusAdapter) {
+	return func(p *PrometheusAdapter) {
+		p.exemplars = enabled
+	}
+}
+
+// Registry returns the registry collectors are registered on, e.g. for promhttp.HandlerFor.
+func (p *PrometheusAdapter) Registry() *prom.Registry {
+	return p.registry
+}
+
+func (p *PrometheusAdapter) Counter(name string, opts ...owl.MetricOption) owl.Counter {
+	return &promCounter{p: p, name: name, cfg: owl.NewMetricConfig(opts...)}
+}
+
+func (p *PrometheusAdapter) Histogram(name string, opts ...owl.MetricOption) owl.Histogram {
+	return &promHistogram{p: p, name: name, cfg: owl.NewMetricConfig(opts...)}
+}
+
+func (p *PrometheusAdapter) Gauge(name string, opts ...owl.MetricOption) owl.Gauge {
+	return &promGauge{p: p, name: name, cfg: owl.NewMetricConfig(opts...)}
+}
+
+func (p *PrometheusAdapter) UpDownCounter(name string, opts ...owl.MetricOption) owl.UpDownCounter {
+	return &promGauge{p: p, name: name, cfg: owl.NewMetricConfig(opts...)}
+}
+
+// ObservableGauge registers a GaugeFunc calling callback on every scrape,
+// with a background context. Registration errors are logged through owl.GetLogger().
+func (p *PrometheusAdapter) ObservableGauge(name string, callback func(ctx context.Context) float64, opts ...owl.MetricOption) {
+	cfg := owl.NewMetricConfig(opts...)
+	gauge := prom.NewGaugeFunc(prom.GaugeOpts{Name: name, Help: help(name, cfg)}, func() float64 {
+		return callback(context.Background())
+	})
+	if err := p.registry.Register(gauge); err != nil {
+		p.logDropped(context.Background(), name, err)
+	}
+}
+
+// counterVec returns the CounterVec for name, creating it with labels on first use.
+func (p *PrometheusAdapter) counterVec(name string, cfg owl.MetricConfig, labels []string) (*prom.CounterVec, error) {
+	if vec, ok := p.counters.Load(name); ok {
+		return vec.(*prom.CounterVec), nil
+	}
+	return createVec(p, &p.counters, name, func() (*prom.CounterVec, error) {
+		return register(p.registry, prom.NewCounterVec(prom.CounterOpts{Name: name, Help: help(name, cfg)}, labels))
+	})
+}
+
+// histogramVec returns the HistogramVec for name, creating it with labels on first use.
+func (p *PrometheusAdapter) histogramVec(name string, cfg owl.MetricConfig, labels []string) (*prom.HistogramVec, error) {
+	if vec, ok := p.histograms.Load(name); ok {
+		return vec.(*prom.HistogramVec), nil
+	}
+	return createVec(p, &p.histograms, name, func() (*prom.HistogramVec, error) {
+		return register(p.registry, prom.NewHistogramVec(prom.HistogramOpts{Name: name, Help: help(name, cfg), Buckets: cfg.Buckets}, labels))
+	})
+}
+
+// gaugeVec returns the GaugeVec for name, creating it with labels on first use.
+func (p *PrometheusAdapter) gaugeVec(name string, cfg owl.MetricConfig, labels []string) (*prom.GaugeVec, error) {
+	if vec, ok := p.gauges.Load(name); ok {
+		return vec.(*prom.GaugeVec), nil
+	}
+	return createVec(p, &p.gauges, name, func() (*prom.GaugeVec, error) {
+		return register(p.registry, prom.NewGaugeVec(prom.GaugeOpts{Name: name, Help: help(name, cfg)}, labels))
+	})
+}
+
+// createVec creates the vec for name in vecs under p.mu, unless a concurrent
+// caller already did. Failed creations are not cached, so a later call retries.
+func createVec[V any](p *PrometheusAdapter, vecs *sync.Map, name string, create func() (V, error)) (V, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if vec, ok := vecs.Load(name); ok {
+		return vec.(V), nil
+	}
+	vec, err := create()
+	if err != nil {
+		return vec, err
+	}
+	vecs.Store(name, vec)
+	return vec, nil
+}
+
+// help returns the metric help text, defaulting to its name.
+func help(name string, cfg owl.MetricConfig) string {
+	if cfg.Description != "" {
+		return cfg.Description
+	}
+	return name
+}
+
+// register registers c on reg. If an identical collector is already registered
+// (e.g. by another adapter sharing the registry), the existing one is returned.
+func register[C prom.Collector](reg *prom.Registry, c C) (C, error) {
+	err := reg.Register(c)
+	var are prom.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		if existing, ok := are.ExistingCollector.(C); ok {
+			return existing, nil
+		}
+	}
+	return c, err
+}
+
+// Wrappers
+
+type promCounter struct {
+	p    *PrometheusAdapter
+	name string
+	cfg  owl.MetricConfig
+}
+
+func (c *promCounter) Inc(ctx context.Context, attrs ...owl.Attribute) {
+	c.Add(ctx, 1, attrs...)
+}
+
+func (c *promCounter) Add(ctx context.Context, delta float64, attrs ...owl.Attribute) {
+	keys, labels := toLabels(attrs)
+	vec, err := c.p.counterVec(c.name, c.cfg, keys)
+	if err == nil {
+		var counter prom.Counter
+		if counter, err = vec.GetMetricWith(labels); err == nil {
+			counter.Add(delta)
+			return
+		}
+	}
+	c.p.logDropped(ctx, c.name, err)
+}
+
+type promHistogram struct {
+	p    *PrometheusAdapter
+	name string
+	cfg  owl.MetricConfig
+}
+
+func (h *promHistogram) Record(ctx context.Context, value float64, attrs ...owl.Attribute) {
+	keys, labels := toLabels(attrs)
+	vec, err := h.p.histogramVec(h.name, h.cfg, keys)
+	if err == nil {
+		var obs prom.Observer
+		if obs, err = vec.GetMetricWith(labels); err == nil {
+			if sc := trace.SpanContextFromContext(ctx); h.p.exemplars && sc.IsSampled() {
+				if eo, ok := obs.(prom.ExemplarObserver); ok {
+					eo.ObserveWithExemplar(value, prom.Labels{"trace_id": sc.TraceID().String()})
+					return
+				}
+			}
+			obs.Observe(value)
+			return
+		}
+	}
+	h.p.logDropped(ctx, h.name, err)
+}
+
+type promGauge struct {
+	p    *PrometheusAdapter
+	name string
+	cfg  owl.MetricConfig
+}
+
+func (g *promGauge) Set(ctx context.Context, value float64, attrs ...owl.Attribute) {
+	if gauge, err := g.gauge(attrs); err == nil {
+		gauge.Set(value)
+	} else {
+		g.p.logDropped(ctx, g.name, err)
+	}
+}
+
+func (g *promGauge) Add(ctx context.Context, delta float64, attrs ...owl.Attribute) {
+	if gauge, err := g.gauge(attrs); err == nil {
+		gauge.Add(delta)
+	} else {
+		g.p.logDropped(ctx, g.name, err)
+	}
+}
+
+func (g *promGauge) gauge(attrs []owl.Attribute) (prom.Gauge, error) {
+	keys, labels := toLabels(attrs)
+	vec, err := g.p.gaugeVec(g.name, g.cfg, keys)
+	if err != nil {
+		return nil, err
+	}
+	return vec.GetMetricWith(labels)
+}
+
+// toLabels converts attributes into sorted label names and a label map.
+func toLabels(attrs []owl.Attribute) ([]string, prom.Labels) {
+	labels := make(prom.Labels, len(attrs))
+	keys := make([]string, 0, len(attrs))
+	for _, a := range attrs {
+		if _, dup := labels[a.Key]; !dup {
+			keys = append(keys, a.Key)
+		}
+		labels[a.Key] = a.Value
+	}
+	sort.Strings(keys)
+	return keys, labels
+}
+
+// logDropped logs the first dropped observation of a metric only, so a caller
+// with mismatched labels on a hot path does not flood the error log.
+func (p *PrometheusAdapter) logDropped(ctx context.Context, name string, err error) {
+	if _, logged := p.dropped.LoadOrStore(name, struct{}{}); logged {
+		return
+	}
+	owl.GetLogger().Error(ctx, "prometheus_observation_dropped", err, "metric", name)
+}
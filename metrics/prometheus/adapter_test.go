@@ -0,0 +1,138 @@
+package prometheus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/myuser/owl"
+	"github.com/myuser/owl/owltest"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestPrometheusAdapter(t *testing.T) {
+	adapter := NewPrometheusAdapter(nil)
+	ctx := context.Background()
+
+	t.Run("Counter", func(t *testing.T) {
+		counter := adapter.Counter("test_counter_total")
+		counter.Inc(ctx, owl.Attr("method", "GET"), owl.Attr("status", "200"))
+		counter.Add(ctx, 2, owl.Attr("status", "200"), owl.Attr("method", "GET"))
+
+		vec, _ := adapter.counterVec("test_counter_total", owl.MetricConfig{}, nil)
+		if got := testutil.ToFloat64(vec.WithLabelValues("GET", "200")); got != 3 {
+			t.Errorf("Expected 3, got %v", got)
+		}
+	})
+
+	t.Run("Histogram", func(t *testing.T) {
+		histo := adapter.Histogram("test_duration_seconds")
+		histo.Record(ctx, 0.2, owl.Attr("method", "GET"))
+		histo.Record(ctx, 0.4, owl.Attr("method", "GET"))
+
+		if n := testutil.CollectAndCount(adapter.Registry(), "test_duration_seconds"); n != 1 {
+			t.Errorf("Expected 1 histogram series, got %d", n)
+		}
+	})
+
+	t.Run("Gauge", func(t *testing.T) {
+		adapter.Gauge("queue_depth").Set(ctx, 7, owl.Attr("queue", "emails"))
+		inflight := adapter.UpDownCounter("in_flight")
+		inflight.Add(ctx, 2)
+		inflight.Add(ctx, -1)
+
+		vec, _ := adapter.gaugeVec("queue_depth", owl.MetricConfig{}, nil)
+		if got := testutil.ToFloat64(vec.WithLabelValues("emails")); got != 7 {
+			t.Errorf("Expected 7, got %v", got)
+		}
+		vec, _ = adapter.gaugeVec("in_flight", owl.MetricConfig{}, nil)
+		if got := testutil.ToFloat64(vec.WithLabelValues()); got != 1 {
+			t.Errorf("Expected 1, got %v", got)
+		}
+	})
+
+	t.Run("Label mismatch", func(t *testing.T) {
+		logger := owltest.NewLogger()
+		owl.SetLogger(logger)
+		defer owl.SetLogger(owl.NoOpLogger{})
+
+		counter := adapter.Counter("mismatch_total")
+		counter.Inc(ctx, owl.Attr("method", "GET"))
+		counter.Inc(ctx, owl.Attr("path", "/"))
+		counter.Inc(ctx, owl.Attr("path", "/"))
+
+		if n := logger.CountLevel("ERROR"); n != 1 {
+			t.Errorf("Expected the dropped observations logged once, got %d", n)
+		}
+	})
+}
+
+func TestPrometheusAdapter_SharedRegistry(t *testing.T) {
+	a := NewPrometheusAdapter(nil)
+	b := NewPrometheusAdapter(a.Registry())
+	ctx := context.Background()
+
+	a.Counter("shared_total").Inc(ctx, owl.Attr("k", "v"))
+	b.Counter("shared_total").Inc(ctx, owl.Attr("k", "v"))
+
+	vec, _ := a.counterVec("shared_total", owl.MetricConfig{}, nil)
+	if got := testutil.ToFloat64(vec.WithLabelValues("v")); got != 2 {
+		t.Errorf("Expected both adapters to share the collector, got %v", got)
+	}
+}
+
+func TestPrometheusAdapter_Exemplars(t *testing.T) {
+	adapter := NewPrometheusAdapter(nil, WithExemplars(true))
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{2},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	h := adapter.Histogram("request_duration_seconds", owl.WithBuckets([]float64{0.1, 1}))
+	h.Record(ctx, 0.05)
+	h.Record(context.Background(), 0.5) // No span, no exemplar
+
+	families, err := adapter.Registry().Gather()
+	if err != nil || len(families) != 1 {
+		t.Fatalf("Gather() = %v, %v", families, err)
+	}
+	buckets := families[0].GetMetric()[0].GetHistogram().GetBucket()
+	ex := buckets[0].GetExemplar()
+	if ex == nil {
+		t.Fatal("Expected an exemplar on the first bucket")
+	}
+	if got := ex.GetLabel()[0]; got.GetName() != "trace_id" || got.GetValue() != sc.TraceID().String() {
+		t.Errorf("Unexpected exemplar label %v", got)
+	}
+	if buckets[1].GetExemplar() != nil {
+		t.Error("Expected no exemplar without a span")
+	}
+}
+
+func TestPrometheusAdapter_ObservableGauge(t *testing.T) {
+	adapter := NewPrometheusAdapter(nil)
+	adapter.ObservableGauge("goroutines", func(ctx context.Context) float64 { return 42 })
+
+	families, err := adapter.Registry().Gather()
+	if err != nil || len(families) != 1 {
+		t.Fatalf("Gather() = %v, %v", families, err)
+	}
+	if got := families[0].GetMetric()[0].GetGauge().GetValue(); got != 42 {
+		t.Errorf("Expected 42, got %v", got)
+	}
+}
+
+func BenchmarkPrometheusAdapter_Counter(b *testing.B) {
+	counter := NewPrometheusAdapter(nil).Counter("requests_total")
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			counter.Inc(ctx, owl.Attr("method", "GET"))
+		}
+	})
+}
@@ -0,0 +1,32 @@
+package prometheus_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/myuser/owl"
+	owlprom "github.com/myuser/owl/metrics/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func Example() {
+	monitor := owlprom.NewPrometheusAdapter(nil)
+	monitor.Counter("jobs_processed_total").Inc(context.Background(), owl.Attr("queue", "emails"))
+
+	// Expose the registry; in production mount this on your mux at /metrics.
+	handler := promhttp.HandlerFor(monitor.Registry(), promhttp.HandlerOpts{})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.HasPrefix(line, "jobs_processed_total") {
+			fmt.Println(line)
+		}
+	}
+	// Output: jobs_processed_total{queue="emails"} 1
+}
@@ -0,0 +1,226 @@
+// Package statsd provides an owl.Monitor that emits StatsD packets, with
+// attributes rendered as DogStatsD tags.
+package statsd
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/myuser/owl"
+)
+
+// Metric types used for histograms, see WithHistogramType.
+const (
+	TypeDistribution = "d"  // DogStatsD distribution (default)
+	TypeHistogram    = "h"  // DogStatsD histogram
+	TypeTiming       = "ms" // Classic StatsD timer
+)
+
+// StatsDAdapter implements owl.Monitor by writing one StatsD packet per
+// observation to a writer, typically a UDP connection to the StatsD agent:
+//
+//	conn, err := net.Dial("udp", "127.0.0.1:8125")
+//	monitor := statsd.NewStatsDAdapter(conn, statsd.WithPrefix("billing."))
+//
+// StatsD is fire-and-forget: write errors are ignored.
+type StatsDAdapter struct {
+	prefix        string
+	histogramType string
+
+	mu          sync.Mutex // Serializes writes and guards the maps below
+	w           io.Writer
+	sums        map[string]float64 // Non-zero UpDownCounter values by metric and tags
+	observables map[string]func(ctx context.Context) float64
+}
+
+// NewStatsDAdapter creates an adapter writing packets to w.
+// If w is nil, packets are discarded.
+func NewStatsDAdapter(w io.Writer, opts ...func(*StatsDAdapter)) *StatsDAdapter {
+	if w == nil {
+		w = io.Discard
+	}
+	a := &StatsDAdapter{
+		histogramType: TypeDistribution,
+		w:             w,
+		sums:          make(map[string]float64),
+		observables:   make(map[string]func(ctx context.Context) float64),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// WithPrefix prepends prefix (e.g. "billing.") to every metric name.
+func WithPrefix(prefix string) func(*StatsDAdapter) {
+	return func(a *StatsDAdapter) {
+		a.prefix = prefix
+	}
+}
+
+// WithHistogramType sets the packet type of histogram observations:
+// TypeDistribution (default), TypeHistogram or TypeTiming.
+func WithHistogramType(t string) func(*StatsDAdapter) {
+	return func(a *StatsDAdapter) {
+		a.histogramType = t
+	}
+}
+
+func (a *StatsDAdapter) Counter(name string, opts ...owl.MetricOption) owl.Counter {
+	return &statsdCounter{a: a, name: name}
+}
+
+func (a *StatsDAdapter) Histogram(name string, opts ...owl.MetricOption) owl.Histogram {
+	return &statsdHistogram{a: a, name: name}
+}
+
+func (a *StatsDAdapter) Gauge(name string, opts ...owl.MetricOption) owl.Gauge {
+	return &statsdGauge{a: a, name: name}
+}
+
+// UpDownCounter keeps the running value per attribute set and sends it as a
+// gauge, since DogStatsD has no signed counter. A value is forgotten when it
+// returns to 0 (e.g. an in-flight count once requests finish), so memory grows
+// only with the attribute sets currently holding a non-zero value; keep their
+// cardinality bounded as for any other metric.
+func (a *StatsDAdapter) UpDownCounter(name string, opts ...owl.MetricOption) owl.UpDownCounter {
+	return &statsdGauge{a: a, name: name}
+}
+
+// ObservableGauge registers callback; its value is sent as a gauge on every
+// tick of Start.
+func (a *StatsDAdapter) ObservableGauge(name string, callback func(ctx context.Context) float64, opts ...owl.MetricOption) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.observables[name] = callback
+}
+
+// Start sends the observable gauges every interval until ctx is done.
+// StatsD is push-based, so observable gauges are not sent without it.
+func (a *StatsDAdapter) Start(ctx context.Context, interval time.Duration) {
+	owl.Go(ctx, func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				a.observe(ctx)
+			}
+		}
+	})
+}
+
+func (a *StatsDAdapter) observe(ctx context.Context) {
+	a.mu.Lock()
+	names := make([]string, 0, len(a.observables))
+	for name := range a.observables {
+		names = append(names, name)
+	}
+	a.mu.Unlock()
+	sort.Strings(names)
+
+	for _, name := range names {
+		a.mu.Lock()
+		callback := a.observables[name]
+		a.mu.Unlock()
+		a.send(name, callback(ctx), "g", nil)
+	}
+}
+
+// send writes one packet for name, value and attrs.
+func (a *StatsDAdapter) send(name string, value float64, typ string, attrs []owl.Attribute) {
+	metric, tags := a.series(name, attrs)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.write(metric, tags, value, typ)
+}
+
+// write writes "<metric>:<value>|<type>|#<tags>". The caller holds a.mu.
+func (a *StatsDAdapter) write(metric, tags string, value float64, typ string) {
+	var b strings.Builder
+	b.WriteString(metric)
+	b.WriteByte(':')
+	b.WriteString(strconv.FormatFloat(value, 'f', -1, 64))
+	b.WriteByte('|')
+	b.WriteString(typ)
+	if tags != "" {
+		b.WriteString("|#")
+		b.WriteString(tags)
+	}
+	_, _ = io.WriteString(a.w, b.String())
+}
+
+// series returns the sanitized metric name and sorted DogStatsD tags.
+func (a *StatsDAdapter) series(name string, attrs []owl.Attribute) (metric, tags string) {
+	pairs := make([]string, len(attrs))
+	for i, attr := range attrs {
+		pairs[i] = sanitize(attr.Key) + ":" + sanitize(attr.Value)
+	}
+	sort.Strings(pairs)
+	return sanitize(a.prefix + name), strings.Join(pairs, ",")
+}
+
+// sanitize replaces the characters that delimit StatsD packets and tags.
+var sanitize = strings.NewReplacer(
+	":", "_",
+	"|", "_",
+	",", "_",
+	"#", "_",
+	"@", "_",
+	"\n", "_",
+).Replace
+
+// Wrappers
+
+type statsdCounter struct {
+	a    *StatsDAdapter
+	name string
+}
+
+func (c *statsdCounter) Inc(ctx context.Context, attrs ...owl.Attribute) {
+	c.a.send(c.name, 1, "c", attrs)
+}
+
+func (c *statsdCounter) Add(ctx context.Context, delta float64, attrs ...owl.Attribute) {
+	c.a.send(c.name, delta, "c", attrs)
+}
+
+type statsdHistogram struct {
+	a    *StatsDAdapter
+	name string
+}
+
+func (h *statsdHistogram) Record(ctx context.Context, value float64, attrs ...owl.Attribute) {
+	h.a.send(h.name, value, h.a.histogramType, attrs)
+}
+
+type statsdGauge struct {
+	a    *StatsDAdapter
+	name string
+}
+
+func (g *statsdGauge) Set(ctx context.Context, value float64, attrs ...owl.Attribute) {
+	g.a.send(g.name, value, "g", attrs)
+}
+
+func (g *statsdGauge) Add(ctx context.Context, delta float64, attrs ...owl.Attribute) {
+	metric, tags := g.a.series(g.name, attrs)
+	key := metric + "|" + tags
+	g.a.mu.Lock()
+	defer g.a.mu.Unlock()
+	sum := g.a.sums[key] + delta
+	if sum == 0 {
+		// The next Add starts from 0 again, so the entry can go
+		delete(g.a.sums, key)
+	} else {
+		g.a.sums[key] = sum
+	}
+	g.a.write(metric, tags, sum, "g")
+}
@@ -0,0 +1,113 @@
+package statsd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/myuser/owl"
+)
+
+// listen starts a fake StatsD agent and returns a connection to it.
+func listen(t *testing.T) (net.Conn, func() string) {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket failed: %v", err)
+	}
+	t.Cleanup(func() { pc.Close() })
+
+	conn, err := net.Dial("udp", pc.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	next := func() string {
+		t.Helper()
+		buf := make([]byte, 1024)
+		pc.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := pc.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("ReadFrom failed: %v", err)
+		}
+		return string(buf[:n])
+	}
+	return conn, next
+}
+
+func TestStatsDAdapter(t *testing.T) {
+	conn, next := listen(t)
+	adapter := NewStatsDAdapter(conn, WithPrefix("billing."))
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		emit func()
+		want string
+	}{
+		{"counter inc", func() {
+			adapter.Counter("requests_total").Inc(ctx, owl.Attr("status", "200"), owl.Attr("method", "GET"))
+		}, "billing.requests_total:1|c|#method:GET,status:200"},
+		{"counter add", func() {
+			adapter.Counter("bytes_total").Add(ctx, 512)
+		}, "billing.bytes_total:512|c"},
+		{"histogram", func() {
+			adapter.Histogram("latency_seconds").Record(ctx, 0.25, owl.Attr("path", "/a|b"))
+		}, "billing.latency_seconds:0.25|d|#path:/a_b"},
+		{"gauge", func() {
+			adapter.Gauge("queue_depth").Set(ctx, 7)
+		}, "billing.queue_depth:7|g"},
+		{"up-down counter", func() {
+			adapter.UpDownCounter("in_flight").Add(ctx, 2)
+			next()
+			adapter.UpDownCounter("in_flight").Add(ctx, -1)
+		}, "billing.in_flight:1|g"},
+		{"tag sanitization", func() {
+			adapter.Counter("events").Inc(ctx, owl.Attr("host:port", "a:1,b#2"))
+		}, "billing.events:1|c|#host_port:a_1_b_2"},
+	}
+	for _, tt := range tests {
+		tt.emit()
+		if got := next(); got != tt.want {
+			t.Errorf("%s: got packet %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStatsDAdapter_UpDownCounterPruning(t *testing.T) {
+	adapter := NewStatsDAdapter(nil)
+	ctx := context.Background()
+	inFlight := adapter.UpDownCounter("in_flight")
+
+	for i := 0; i < 100; i++ {
+		path := owl.Attr("path", fmt.Sprintf("/items/%d", i))
+		inFlight.Add(ctx, 1, path)
+		inFlight.Add(ctx, -1, path)
+	}
+	inFlight.Add(ctx, 1, owl.Attr("path", "/slow"))
+
+	if n := len(adapter.sums); n != 1 {
+		t.Errorf("Expected only the non-zero series kept, got %d", n)
+	}
+}
+
+func TestStatsDAdapter_TimingAndObservables(t *testing.T) {
+	conn, next := listen(t)
+	adapter := NewStatsDAdapter(conn, WithHistogramType(TypeTiming))
+
+	adapter.Histogram("db_query_ms").Record(context.Background(), 12)
+	if got := next(); got != "db_query_ms:12|ms" {
+		t.Errorf("Unexpected timing packet %q", got)
+	}
+
+	adapter.ObservableGauge("goroutines", func(ctx context.Context) float64 { return 42 })
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	adapter.Start(ctx, 5*time.Millisecond)
+	if got := next(); got != "goroutines:42|g" {
+		t.Errorf("Unexpected observable packet %q", got)
+	}
+}
@@ -0,0 +1,236 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/myuser/owl"
+)
+
+// BreakerState is the state of a CircuitBreakerTransport, reported as the
+// value of the "circuit_breaker_state" gauge.
+type BreakerState int
+
+const (
+	BreakerClosed   BreakerState = iota // Requests flow normally
+	BreakerOpen                         // Requests fail fast
+	BreakerHalfOpen                     // A single probe request is allowed through
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half_open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerTransport stops calling a failing downstream. After Threshold
+// consecutive failures (transport errors or responses that CheckResponse
+// hydrates to a 5xx code) it opens and rejects requests with owl.Unavailable.
+// Once Cooldown has passed it lets one probe through: success closes it again,
+// failure re-opens it. Requests cancelled by the caller count neither way.
+type CircuitBreakerTransport struct {
+	Base      http.RoundTripper
+	Threshold int
+	Cooldown  time.Duration
+
+	name    string
+	monitor owl.Monitor
+	gauge   owl.Gauge
+	now     func() time.Time
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewCircuitBreakerTransport wraps base with a circuit breaker.
+// Defaults: opens after 5 consecutive failures, 30s cooldown.
+func NewCircuitBreakerTransport(base http.RoundTripper, opts ...func(*CircuitBreakerTransport)) *CircuitBreakerTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &CircuitBreakerTransport{
+		Base:      base,
+		Threshold: 5,
+		Cooldown:  30 * time.Second,
+		monitor:   owl.NoOpMonitor{},
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	t.gauge = t.monitor.Gauge("circuit_breaker_state",
+		owl.WithDescription("Circuit breaker state: 0 closed, 1 open, 2 half-open."),
+	)
+	t.gauge.Set(context.Background(), float64(BreakerClosed), t.attrs()...)
+	return t
+}
+
+// WithBreakerThreshold sets the number of consecutive failures that opens the circuit.
+func WithBreakerThreshold(n int) func(*CircuitBreakerTransport) {
+	return func(t *CircuitBreakerTransport) {
+		t.Threshold = n
+	}
+}
+
+// WithBreakerCooldown sets how long the circuit stays open before probing.
+func WithBreakerCooldown(d time.Duration) func(*CircuitBreakerTransport) {
+	return func(t *CircuitBreakerTransport) {
+		t.Cooldown = d
+	}
+}
+
+// WithBreakerMonitor records state changes to the "circuit_breaker_state" gauge of m.
+func WithBreakerMonitor(m owl.Monitor) func(*CircuitBreakerTransport) {
+	return func(t *CircuitBreakerTransport) {
+		if m != nil {
+			t.monitor = m
+		}
+	}
+}
+
+// WithBreakerName sets the "name" attribute of the state gauge, to tell breakers apart.
+func WithBreakerName(name string) func(*CircuitBreakerTransport) {
+	return func(t *CircuitBreakerTransport) {
+		t.name = name
+	}
+}
+
+// WithCircuitBreaker adds a CircuitBreakerTransport to an InstrumentedClient.
+func WithCircuitBreaker(opts ...func(*CircuitBreakerTransport)) ClientOption {
+	return WithTransportMiddleware(func(next http.RoundTripper) http.RoundTripper {
+		return NewCircuitBreakerTransport(next, opts...)
+	})
+}
+
+// State returns the current state of the breaker.
+func (t *CircuitBreakerTransport) State() BreakerState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.state
+}
+
+// RoundTrip executes the request unless the circuit is open.
+func (t *CircuitBreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.allow(req) {
+		return nil, owl.Problem(owl.Unavailable,
+			owl.WithOp("CircuitBreaker.RoundTrip"),
+			owl.WithMsg("circuit open for "+req.URL.Host),
+			owl.WithSafeMsg("circuit open"),
+		)
+	}
+
+	resp, err := t.Base.RoundTrip(req)
+	t.record(req, breakerOutcomeOf(resp, err))
+	return resp, err
+}
+
+// allow reports whether a request may proceed, moving open to half-open after the cooldown.
+func (t *CircuitBreakerTransport) allow(req *http.Request) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch t.state {
+	case BreakerOpen:
+		if t.now().Sub(t.openedAt) < t.Cooldown {
+			return false
+		}
+		t.setState(req, BreakerHalfOpen)
+		t.probing = true
+		return true
+	case BreakerHalfOpen:
+		// Only one probe at a time
+		if t.probing {
+			return false
+		}
+		t.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// record updates the breaker with the outcome of a request. An ignored
+// outcome only frees the half-open probe slot, so the next request probes.
+func (t *CircuitBreakerTransport) record(req *http.Request, outcome breakerOutcome) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.state == BreakerHalfOpen {
+		t.probing = false
+		switch outcome {
+		case breakerFailure:
+			t.open(req)
+		case breakerSuccess:
+			t.failures = 0
+			t.setState(req, BreakerClosed)
+		}
+		return
+	}
+
+	switch outcome {
+	case breakerIgnored:
+		return
+	case breakerSuccess:
+		t.failures = 0
+		return
+	}
+	t.failures++
+	if t.state == BreakerClosed && t.failures >= t.Threshold {
+		t.open(req)
+	}
+}
+
+func (t *CircuitBreakerTransport) open(req *http.Request) {
+	t.openedAt = t.now()
+	t.setState(req, BreakerOpen)
+}
+
+func (t *CircuitBreakerTransport) setState(req *http.Request, s BreakerState) {
+	t.state = s
+	t.gauge.Set(req.Context(), float64(s), t.attrs()...)
+}
+
+func (t *CircuitBreakerTransport) attrs() []owl.Attribute {
+	if t.name == "" {
+		return nil
+	}
+	return []owl.Attribute{owl.Attr("name", t.name)}
+}
+
+// breakerOutcome is how a request's outcome counts for the breaker.
+type breakerOutcome int
+
+const (
+	breakerSuccess breakerOutcome = iota // The downstream answered correctly
+	breakerFailure                       // The downstream failed
+	breakerIgnored                       // Says nothing about the downstream
+)
+
+// breakerOutcomeOf classifies a request's outcome. Client errors (4xx) are
+// successes: the downstream answered correctly.
+func breakerOutcomeOf(resp *http.Response, err error) breakerOutcome {
+	if err != nil {
+		// The caller giving up says nothing about the downstream
+		if errors.Is(err, context.Canceled) {
+			return breakerIgnored
+		}
+		return breakerFailure
+	}
+	if hydrated := CheckResponse(resp); hydrated != nil && owl.ToHTTPStatus(hydrated) >= 500 {
+		return breakerFailure
+	}
+	return breakerSuccess
+}
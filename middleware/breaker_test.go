@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/myuser/owl"
+	"github.com/myuser/owl/owltest"
+)
+
+func TestCircuitBreakerTransport(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	calls := 0
+	base := &mockTransport{RoundTripFunc: func(r *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: status, Header: make(http.Header), Body: http.NoBody}, nil
+	}}
+
+	monitor := owltest.NewMonitor()
+	now := time.Now()
+	cb := NewCircuitBreakerTransport(base,
+		WithBreakerThreshold(2),
+		WithBreakerCooldown(time.Minute),
+		WithBreakerMonitor(monitor),
+	)
+	cb.now = func() time.Time { return now }
+	client := &http.Client{Transport: cb}
+
+	get := func() error {
+		resp, err := client.Get("http://downstream/")
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	// Closed -> Open after 2 consecutive failures
+	get()
+	get()
+	if cb.State() != BreakerOpen {
+		t.Fatalf("Expected open after threshold, got %v", cb.State())
+	}
+	if got := monitor.GetGauge("circuit_breaker_state"); got != float64(BreakerOpen) {
+		t.Errorf("Expected gauge %d, got %v", BreakerOpen, got)
+	}
+
+	// Open: fail fast without calling the downstream
+	err := get()
+	var e *owl.Error
+	if !errors.As(err, &e) || e.Code != owl.Unavailable || e.SafeMsg != "circuit open" {
+		t.Errorf("Expected circuit open error, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected no downstream call while open, got %d calls", calls)
+	}
+
+	// Half-open probe fails -> Open again
+	now = now.Add(time.Minute)
+	get()
+	if cb.State() != BreakerOpen || calls != 3 {
+		t.Fatalf("Expected failed probe to re-open, got %v after %d calls", cb.State(), calls)
+	}
+
+	// Half-open probe succeeds -> Closed
+	now = now.Add(time.Minute)
+	status = http.StatusOK
+	if err := get(); err != nil {
+		t.Fatalf("Expected probe to pass, got %v", err)
+	}
+	if cb.State() != BreakerClosed {
+		t.Errorf("Expected closed after successful probe, got %v", cb.State())
+	}
+	if got := monitor.GetGauge("circuit_breaker_state"); got != float64(BreakerClosed) {
+		t.Errorf("Expected gauge %d, got %v", BreakerClosed, got)
+	}
+}
+
+func TestCircuitBreakerTransport_IgnoresClientErrors(t *testing.T) {
+	base := &mockTransport{RoundTripFunc: func(r *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusNotFound, Header: make(http.Header), Body: http.NoBody}, nil
+	}}
+	cb := NewCircuitBreakerTransport(base, WithBreakerThreshold(1))
+	req, _ := http.NewRequest(http.MethodGet, "http://downstream/", nil)
+
+	for i := 0; i < 3; i++ {
+		resp, err := cb.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if cb.State() != BreakerClosed {
+		t.Errorf("Expected 404s not to open the circuit, got %v", cb.State())
+	}
+}
+
+func TestCircuitBreakerTransport_IgnoresCancellation(t *testing.T) {
+	cancelled := false
+	base := &mockTransport{RoundTripFunc: func(r *http.Request) (*http.Response, error) {
+		if cancelled {
+			return nil, context.Canceled
+		}
+		return nil, errors.New("connection refused")
+	}}
+	now := time.Now()
+	cb := NewCircuitBreakerTransport(base, WithBreakerThreshold(2), WithBreakerCooldown(time.Minute))
+	cb.now = func() time.Time { return now }
+	req, _ := http.NewRequest(http.MethodGet, "http://downstream/", nil)
+
+	// A cancellation between failures neither resets nor adds to the run
+	cb.RoundTrip(req)
+	cancelled = true
+	cb.RoundTrip(req)
+	cancelled = false
+	cb.RoundTrip(req)
+	if cb.State() != BreakerOpen {
+		t.Fatalf("Expected open after 2 failures around a cancellation, got %v", cb.State())
+	}
+
+	// A cancelled probe keeps the circuit half-open and frees the probe slot
+	now = now.Add(time.Minute)
+	cancelled = true
+	cb.RoundTrip(req)
+	if cb.State() != BreakerHalfOpen {
+		t.Errorf("Expected half-open after a cancelled probe, got %v", cb.State())
+	}
+	if !cb.allow(req) {
+		t.Error("Expected another probe to be allowed")
+	}
+}
@@ -0,0 +1,23 @@
+package middleware
+
+// HTTPMiddleware decorates an HTTPHandler with a cross-cutting concern (auth, timeouts, ...).
+type HTTPMiddleware func(HTTPHandler) HTTPHandler
+
+// Chain composes middlewares into one, outermost first:
+// Chain(a, b)(h) runs a, then b, then h.
+func Chain(middlewares ...HTTPMiddleware) HTTPMiddleware {
+	return func(h HTTPHandler) HTTPHandler {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			h = middlewares[i](h)
+		}
+		return h
+	}
+}
+
+// Use registers middlewares applied by every subsequent Wrap, outermost first.
+// They run inside the factory's instrumentation, so their errors are encoded and
+// logged and their panics recovered like the handler's. Call Use during setup,
+// before the factory serves requests.
+func (f *HTTPFactory) Use(middlewares ...HTTPMiddleware) {
+	f.middlewares = append(f.middlewares, middlewares...)
+}
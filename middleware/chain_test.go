@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestChain(t *testing.T) {
+	var order []string
+	mw := func(name string) HTTPMiddleware {
+		return func(next HTTPHandler) HTTPHandler {
+			return func(w http.ResponseWriter, r *http.Request) error {
+				order = append(order, name)
+				return next(w, r)
+			}
+		}
+	}
+
+	f := NewHTTPFactory(nil, nil)
+	f.Use(mw("factory"))
+	h := f.Wrap(Chain(mw("outer"), mw("inner"))(func(w http.ResponseWriter, r *http.Request) error {
+		order = append(order, "handler")
+		return nil
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	want := []string{"factory", "outer", "inner", "handler"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("Expected order %v, got %v", want, order)
+	}
+}
+
+func TestChain_PanicInMiddleware(t *testing.T) {
+	f := NewHTTPFactory(nil, nil)
+	f.Use(func(next HTTPHandler) HTTPHandler {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			panic("auth exploded")
+		}
+	})
+	h := f.Wrap(func(w http.ResponseWriter, r *http.Request) error { return nil })
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected 500 after middleware panic, got %d", w.Code)
+	}
+}
@@ -1,19 +1,22 @@
 package middleware
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"io"
 	"net/http"
+	"net/url"
+	"sync"
 	"time"
 
 	"github.com/myuser/owl"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 // HTTPClient wraps a standard http.RoundTripper to handle Trace Injection and Error Hydration.
@@ -39,22 +42,35 @@ func NewHTTPClient(base http.RoundTripper, logger owl.Logger) *HTTPClient {
 // RoundTrip executes the HTTP transaction.
 func (c *HTTPClient) RoundTrip(req *http.Request) (*http.Response, error) {
 	start := time.Now()
-	ctx := req.Context()
 
-	// 1. Trace Injection (W3C)
+	// 1. Client Span
+	ctx, end := owl.Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.method", req.Method),
+			attribute.String("http.url", redactURL(req.URL)),
+		),
+	)
+	req = req.WithContext(ctx)
+
+	// 2. Trace Injection (W3C)
 	// Inject the current trace context into the headers so the upstream service can continue the trace.
 	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
 
-	// 2. Execution
+	// 3. Execution
 	resp, err := c.Base.RoundTrip(req)
 	duration := time.Since(start).Seconds()
+	if resp != nil {
+		trace.SpanFromContext(ctx).SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	}
+	end(&err)
 
-	// 3. Logging
+	// 4. Logging
 	if err != nil {
 		c.Logger.Error(ctx, "outbound_request_failed", err,
 			"duration", duration,
 			"method", req.Method,
-			"url", req.URL.String(),
+			"url", redactURL(req.URL),
 		)
 		return nil, err
 	}
@@ -62,84 +78,38 @@ func (c *HTTPClient) RoundTrip(req *http.Request) (*http.Response, error) {
 	c.Logger.Info(ctx, "outbound_request_success",
 		"duration", duration,
 		"method", req.Method,
-		"url", req.URL.String(),
+		"url", redactURL(req.URL),
 		"status", resp.StatusCode,
 	)
 
 	return resp, nil
 }
 
-// Helper for HTTP Response Hydration
-func CheckResponse(resp *http.Response) error {
-	if resp.StatusCode < 400 {
-		return nil
-	}
-
-	// Read body (non-destructive if we buffer, but here we assume we consume it)
-	// We LIMIT the read to prevent OOM on massive bodies.
-	// 64KB is sufficient for any reasonable error JSON.
-	defer resp.Body.Close()
-
-	// Only attempt JSON decode if Content-Type looks like JSON
-	ct := resp.Header.Get("Content-Type")
-	isJSON := false
-	if len(ct) >= 16 && ct[:16] == "application/json" {
-		isJSON = true
-	} else if len(ct) >= 15 && ct[:15] == "application/json" {
-		// "application/json" is 16 chars... wait.
-		// "application/json" is 16 chars.
-		isJSON = true
-	} else {
-		// simple check
-		if ct == "application/json" {
-			isJSON = true
-		}
-	}
-	// Simplified check
-	// Note: Strings.Contains is often safer for "application/json; charset=utf-8"
-	// but let's just proceed with safe Logic.
-
-	// Limit read to 64KB for safety
-	limitReader := io.LimitReader(resp.Body, 64*1024)
-	body, _ := io.ReadAll(limitReader)
-
-	// CRITICAL FIX: Restore the response body so downstream consumers can read it.
-	// We matched up to 64KB. We need to construct a reader that:
-	// 1. Reads the bytes we just consumed
-	// 2. Reads the rest of the original resp.Body
-	// 3. Closes the original resp.Body when Close() is called
-	resp.Body = &compositeReadCloser{
-		Reader: io.MultiReader(bytes.NewReader(body), resp.Body),
-		Closer: resp.Body,
-	}
-
-	if isJSON || (len(body) > 0 && body[0] == '{') {
-		var owlErr owl.Error
-		if err := json.Unmarshal(body, &owlErr); err == nil && owlErr.Code != 0 {
-			return &owlErr
-		}
-	}
-
-	// Fallback using status code reverse mapping
-	// If body is text, include it in the Msg for debugging
-	return owl.Problem(
-		owl.FromHTTPStatus(resp.StatusCode),
-		owl.WithMsg(string(body)),
-	)
-}
-
-// compositeReadCloser combines a Reader (for the restored body) and a Closer (the original body).
-type compositeReadCloser struct {
-	io.Reader
-	io.Closer
+// redactURL returns u without its query and user info, which commonly carry
+// tokens and credentials, for span attributes and logs.
+func redactURL(u *url.URL) string {
+	r := *u
+	r.User = nil
+	r.RawQuery = ""
+	r.ForceQuery = false
+	return r.String()
 }
 
-func (c *compositeReadCloser) Read(p []byte) (n int, err error) {
-	return c.Reader.Read(p)
+// CheckResponse hydrates an error response (status >= 400) into an *owl.Error.
+// The body is restored afterwards, so callers can still read it, and remains
+// the caller's to close. See owl.FromResponse.
+func CheckResponse(resp *http.Response) error {
+	if e := owl.FromResponse(resp); e != nil {
+		return e
+	}
+	return nil
 }
 
-func (c *compositeReadCloser) Close() error {
-	return c.Closer.Close()
+// SetMaxBodyCapture sets how many bytes of a non-JSON error body CheckResponse
+// keeps in the error Msg (default 1KB). It is the same setting as
+// owl.SetMaxBodyCapture.
+func SetMaxBodyCapture(n int) {
+	owl.SetMaxBodyCapture(n)
 }
 
 // UnaryClientInterceptor returns a new unary client interceptor that injects trace context and logs requests.
@@ -178,22 +148,109 @@ func UnaryClientInterceptor(logger owl.Logger) grpc.UnaryClientInterceptor {
 
 		if err != nil {
 			logger.Error(ctx, "outbound_rpc_failed", err, fields...)
+			return hydrateGRPCError(err)
+		}
+		logger.Info(ctx, "outbound_rpc_success", fields...)
+		return nil
+	}
+}
 
-			// Hydration Logic
-			st, ok := status.FromError(err)
-			if ok {
-				owlCode := owl.FromGRPCStatus(st.Code())
-				return owl.Problem(
-					owlCode,
-					owl.WithMsg(st.Message()), // Use st.Message() as SafeMsg/Msg
-					owl.WithErr(err),
-				)
-			}
+// StreamClientInterceptor returns a new stream client interceptor that injects trace context
+// and logs when the stream is opened and closed.
+func StreamClientInterceptor(logger owl.Logger) grpc.StreamClientInterceptor {
+	if logger == nil {
+		logger = owl.NoOpLogger{}
+	}
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		start := time.Now()
+
+		// 1. Trace Injection
+		md, ok := metadata.FromOutgoingContext(ctx)
+		if !ok {
+			md = metadata.New(nil)
+		}
+		otel.GetTextMapPropagator().Inject(ctx, &metadataSupplier{md})
+		ctx = metadata.NewOutgoingContext(ctx, md)
+
+		// 2. Stream Setup
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			logger.Error(ctx, "outbound_stream_failed", err,
+				"duration", time.Since(start).Seconds(),
+				"method", method,
+			)
+			return nil, hydrateGRPCError(err)
+		}
+
+		logger.Info(ctx, "outbound_stream_opened", "method", method)
+		return &clientStream{
+			ClientStream: cs,
+			ctx:          ctx,
+			logger:       logger,
+			method:       method,
+			start:        start,
+		}, nil
+	}
+}
+
+// clientStream logs once when the stream ends, i.e. when RecvMsg returns an error (io.EOF on success).
+type clientStream struct {
+	grpc.ClientStream
+	ctx    context.Context
+	logger owl.Logger
+	method string
+	start  time.Time
+	once   sync.Once
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == nil {
+		return nil
+	}
+
+	s.once.Do(func() {
+		fields := []any{
+			"duration", time.Since(s.start).Seconds(),
+			"method", s.method,
+		}
+		if err == io.EOF {
+			s.logger.Info(s.ctx, "outbound_stream_closed", fields...)
 		} else {
-			logger.Info(ctx, "outbound_rpc_success", fields...)
+			s.logger.Error(s.ctx, "outbound_stream_failed", err, fields...)
 		}
+	})
+	if err == io.EOF {
 		return err
 	}
+	return hydrateGRPCError(err)
+}
+
+// hydrateGRPCError converts a gRPC status error back into an *owl.Error.
+// Errors without a status are returned unchanged.
+func hydrateGRPCError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	opts := []owl.Option{
+		owl.WithMsg(st.Message()), // Use st.Message() as SafeMsg/Msg
+		owl.WithErr(err),
+	}
+	// Restore SafeDetails attached by owl.ToGRPCStatus
+	for _, d := range st.Details() {
+		if s, ok := d.(*structpb.Struct); ok {
+			opts = append(opts, owl.WithSafeDetails(s.AsMap()))
+		}
+	}
+	return owl.Problem(owl.FromGRPCStatus(st.Code()), opts...)
 }
 
 // metadataSupplier implements propagation.TextMapCarrier
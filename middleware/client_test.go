@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/myuser/owl"
+	"github.com/myuser/owl/owltest"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
@@ -163,3 +164,207 @@ func TestMetadataSupplier(t *testing.T) {
 		t.Errorf("Expected 2 keys, got %d", len(keys))
 	}
 }
+
+func TestUnaryClientInterceptor_HydratesDetails(t *testing.T) {
+	interceptor := UnaryClientInterceptor(nil)
+
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return owl.ToGRPCStatus(owl.Problem(owl.NotFound, owl.WithSafeDetails(map[string]any{"resource_id": "123"}))).Err()
+	}
+
+	err := interceptor(context.Background(), "/test", nil, nil, nil, invoker)
+	var owlErr *owl.Error
+	if !errors.As(err, &owlErr) {
+		t.Fatalf("Expected *owl.Error, got %T", err)
+	}
+	if owlErr.Code != owl.NotFound {
+		t.Errorf("Expected NotFound, got %v", owlErr.Code)
+	}
+	if owlErr.SafeDetails["resource_id"] != "123" {
+		t.Errorf("Expected details to survive, got %v", owlErr.SafeDetails)
+	}
+}
+
+// stubClientStream is a grpc.ClientStream that ends with recvErr.
+type stubClientStream struct {
+	grpc.ClientStream
+	recvErr error
+}
+
+func (s *stubClientStream) RecvMsg(m interface{}) error {
+	return s.recvErr
+}
+
+func TestStreamClientInterceptor(t *testing.T) {
+	withSpanRecorder(t)
+	logger := owltest.NewLogger()
+	interceptor := StreamClientInterceptor(logger)
+
+	ctx, end := owl.Start(context.Background(), "parent")
+	defer end(nil)
+
+	var traceparent string
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		if vals := md.Get("traceparent"); len(vals) > 0 {
+			traceparent = vals[0]
+		}
+		return &stubClientStream{recvErr: io.EOF}, nil
+	}
+
+	cs, err := interceptor(ctx, &grpc.StreamDesc{ServerStreams: true}, nil, "/items.Items/Watch", streamer)
+	if err != nil {
+		t.Fatalf("Interceptor failed: %v", err)
+	}
+	if traceparent == "" {
+		t.Error("Expected traceparent in outgoing metadata")
+	}
+
+	if err := cs.RecvMsg(nil); err != io.EOF {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+	if logger.Find("outbound_stream_opened") == nil || logger.Find("outbound_stream_closed") == nil {
+		t.Error("Expected stream open/close logs")
+	}
+}
+
+func TestStreamClientInterceptor_HydratesSetupError(t *testing.T) {
+	interceptor := StreamClientInterceptor(nil)
+
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return nil, owl.ToGRPCStatus(owl.Problem(owl.Unavailable)).Err()
+	}
+
+	_, err := interceptor(context.Background(), &grpc.StreamDesc{}, nil, "/test", streamer)
+	if !errors.Is(err, owl.Unavailable) {
+		t.Errorf("Expected Unavailable, got %v", err)
+	}
+}
+
+func TestCheckResponse_RateLimited(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{"Content-Type": []string{"text/plain"}},
+		Body:       io.NopCloser(strings.NewReader("slow down")),
+	}
+
+	err := CheckResponse(resp)
+	if !errors.Is(err, owl.ResourceExhausted) {
+		t.Errorf("Expected ResourceExhausted, got %v", err)
+	}
+	if got := owl.ToHTTPStatus(err); got != http.StatusTooManyRequests {
+		t.Errorf("Expected 429 round trip, got %d", got)
+	}
+}
+
+func TestCheckResponse_ProblemJSON(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusNotFound,
+		Header:     http.Header{"Content-Type": []string{"application/problem+json; charset=utf-8"}},
+		Body:       io.NopCloser(strings.NewReader(` {"code":"NOT_FOUND","message":"no such user"}`)),
+	}
+
+	err := CheckResponse(resp)
+	var e *owl.Error
+	if !errors.As(err, &e) || e.Code != owl.NotFound || e.Msg != "no such user" {
+		t.Errorf("Expected hydrated NotFound, got %#v", err)
+	}
+}
+
+// closeTracker records whether the body was closed.
+type closeTracker struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeTracker) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestCheckResponse_LeavesBodyOpen(t *testing.T) {
+	// Larger than the 64KB read limit, so part of it is never buffered
+	body := `{"code":"INVALID","message":"bad"}` + strings.Repeat(" ", 100*1024)
+	tracker := &closeTracker{Reader: strings.NewReader(body)}
+	resp := &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       tracker,
+	}
+
+	_ = CheckResponse(resp)
+	if tracker.closed {
+		t.Fatal("Expected CheckResponse to leave the body open")
+	}
+
+	restored, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read restored body: %v", err)
+	}
+	if string(restored) != body {
+		t.Errorf("Expected the full %d byte body, got %d bytes", len(body), len(restored))
+	}
+
+	resp.Body.Close()
+	if !tracker.closed {
+		t.Error("Expected closing the restored body to close the original")
+	}
+}
+
+func TestCheckResponse_TruncatesTextBody(t *testing.T) {
+	body := "<html>\r\n" + strings.Repeat("x", 10*1024) + "</html>\x00"
+	resp := &http.Response{
+		StatusCode: http.StatusBadGateway,
+		Header:     http.Header{"Content-Type": []string{"text/html"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+
+	var e *owl.Error
+	if !errors.As(CheckResponse(resp), &e) {
+		t.Fatal("Expected *owl.Error")
+	}
+	if e.Code != owl.Internal {
+		t.Errorf("Expected status mapping to be kept, got %v", e.Code)
+	}
+	if !strings.HasSuffix(e.Msg, "…") || len(e.Msg) > 1024+len("…") {
+		t.Errorf("Expected Msg truncated to 1KB, got %d bytes", len(e.Msg))
+	}
+	if strings.ContainsAny(e.Msg, "\r\n\x00") {
+		t.Errorf("Expected control characters to be stripped, got %q", e.Msg[:16])
+	}
+
+	SetMaxBodyCapture(2)
+	defer SetMaxBodyCapture(1024)
+	resp.Body = io.NopCloser(strings.NewReader("héllo"))
+	if errors.As(CheckResponse(resp), &e); e.Msg != "h…" {
+		t.Errorf("Expected rune-safe truncation, got %q", e.Msg)
+	}
+}
+
+func TestCheckResponse_CustomStatusMapping(t *testing.T) {
+	owl.RegisterHTTPStatusMapping(http.StatusUnprocessableEntity, owl.Invalid)
+	owl.RegisterHTTPStatusMapping(460, owl.Unauthorized)
+	t.Cleanup(func() {
+		owl.UnregisterHTTPStatusMapping(http.StatusUnprocessableEntity)
+		owl.UnregisterHTTPStatusMapping(460)
+	})
+
+	for status, want := range map[int]owl.Code{
+		http.StatusUnprocessableEntity: owl.Invalid,
+		460:                            owl.Unauthorized,
+	} {
+		resp := &http.Response{
+			StatusCode: status,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader("nope")),
+		}
+		if err := CheckResponse(resp); !errors.Is(err, want) {
+			t.Errorf("status %d: expected %v, got %v", status, want, err)
+		}
+	}
+
+	owl.UnregisterHTTPStatusMapping(460)
+	if got := owl.FromHTTPStatus(460); got != owl.Invalid {
+		t.Errorf("Expected the built-in 4xx mapping after unregistering, got %v", got)
+	}
+}
@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/myuser/owl"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ToConnectError converts err into a Connect error carrying the mapped code,
+// the public message and the SafeDetails, exactly as owl.ToGRPCStatus does for
+// gRPC. A *connect.Error that is not wrapped in an *owl.Error is returned as is.
+func ToConnectError(err error) *connect.Error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := owl.AsError(err); !ok {
+		var ce *connect.Error
+		if errors.As(err, &ce) {
+			return ce
+		}
+	}
+
+	// Connect codes share their values with gRPC codes
+	st := owl.ToGRPCStatus(err)
+	ce := connect.NewError(connect.Code(st.Code()), errors.New(st.Message()))
+	for _, d := range st.Details() {
+		if m, ok := d.(proto.Message); ok {
+			if detail, err := connect.NewErrorDetail(m); err == nil {
+				ce.AddDetail(detail)
+			}
+		}
+	}
+	return ce
+}
+
+// FromConnectError converts a Connect error back into an *owl.Error, restoring
+// the SafeDetails attached by ToConnectError. Other errors are returned unchanged.
+func FromConnectError(err error) error {
+	var ce *connect.Error
+	if !errors.As(err, &ce) {
+		return err
+	}
+	opts := []owl.Option{
+		owl.WithMsg(ce.Message()),
+		owl.WithErr(err),
+	}
+	for _, d := range ce.Details() {
+		if v, derr := d.Value(); derr == nil {
+			if s, ok := v.(*structpb.Struct); ok {
+				opts = append(opts, owl.WithSafeDetails(s.AsMap()))
+			}
+		}
+	}
+	return owl.Problem(owl.FromConnectCode(ce.Code()), opts...)
+}
+
+// ConnectInterceptor returns a Connect interceptor that logs, meters and traces
+// handler calls like UnaryServerInterceptor, and converts returned errors with
+// ToConnectError. Metrics are "connect_requests_total",
+// "connect_request_duration_seconds" and "connect_requests_in_flight".
+//
+// On clients, calls pass through and unary errors are hydrated with
+// FromConnectError.
+func (f *GRPCFactory) ConnectInterceptor() connect.Interceptor {
+	return &connectInterceptor{
+		f:          f,
+		reqCount:   f.monitor.Counter("connect_requests_total"),
+		reqLatency: f.monitor.Histogram("connect_request_duration_seconds"),
+		inFlight:   f.monitor.UpDownCounter("connect_requests_in_flight"),
+	}
+}
+
+type connectInterceptor struct {
+	f          *GRPCFactory
+	reqCount   owl.Counter
+	reqLatency owl.Histogram
+	inFlight   owl.UpDownCounter
+}
+
+func (i *connectInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			resp, err := next(ctx, req)
+			if err != nil {
+				return nil, FromConnectError(err)
+			}
+			return resp, nil
+		}
+
+		var resp connect.AnyResponse
+		err := i.serve(ctx, req.Spec().Procedure, req.Header(), func(ctx context.Context) error {
+			var err error
+			resp, err = next(ctx, req)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+}
+
+func (i *connectInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler times and reports the whole stream like a unary call.
+func (i *connectInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		return i.serve(ctx, conn.Spec().Procedure, conn.RequestHeader(), func(ctx context.Context) error {
+			return next(ctx, conn)
+		})
+	}
+}
+
+// serve instruments a handler call and returns its error converted for Connect.
+func (i *connectInterceptor) serve(ctx context.Context, procedure string, header http.Header, call func(context.Context) error) error {
+	f := i.f
+	method := owl.Attr("method", owl.NormalizeGRPCMethod(procedure))
+	i.inFlight.Add(ctx, 1, method)
+	defer i.inFlight.Add(ctx, -1, method)
+
+	// 1. Trace Extraction
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+	ctx, endSpan := startServerSpan(ctx, "connect_rpc", procedure)
+	ctx = f.requestScope(ctx, metadata.MD{requestIDMetadataKey: header.Values(requestIDMetadataKey)}, procedure)
+
+	start := time.Now()
+
+	// 2. Execution
+	err := call(ctx)
+	duration := time.Since(start).Seconds()
+
+	// 3. Match code
+	code := "ok"
+	var ce *connect.Error
+	if err != nil {
+		err = mapError(f.errorMapper, err)
+		ce = ToConnectError(err)
+		code = ce.Code().String()
+	}
+
+	// 4. Metrics
+	attrs := append([]owl.Attribute{
+		method,
+		owl.Attr("code", code),
+	}, baggageAttrs(ctx, f.baggageLabels)...)
+	i.reqCount.Inc(ctx, attrs...)
+	i.reqLatency.Record(ctx, duration, attrs...)
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("rpc.connect_rpc.error_code", code))
+	endSpan(&err)
+
+	// 5. Logging
+	if err == nil {
+		f.logger.Info(ctx, "connect_request_success",
+			"code", code,
+			"duration", duration,
+			"method", procedure,
+		)
+		return nil
+	}
+	if obsErr, ok := owl.AsError(err); ok {
+		fields := []any{
+			"code", code,
+			"duration", duration,
+			"method", procedure,
+		}
+		fields = append(fields, errorLogFields(obsErr)...)
+		f.logger.Error(ctx, obsErr.Msg, obsErr.Err, fields...)
+	} else {
+		f.logger.Error(ctx, "connect_request_failed", err,
+			"code", code,
+			"duration", duration,
+			"method", procedure,
+			"error_code", owl.CodeFromError(err).String(),
+		)
+	}
+	return ce
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/myuser/owl"
+	"github.com/myuser/owl/owltest"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestToConnectError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want connect.Code
+	}{
+		{owl.Problem(owl.Invalid), connect.CodeInvalidArgument},
+		{owl.Problem(owl.NotFound), connect.CodeNotFound},
+		{owl.Problem(owl.Internal), connect.CodeInternal},
+		{errors.New("plain"), connect.CodeUnknown},
+	}
+	for _, tt := range tests {
+		if got := ToConnectError(tt.err).Code(); got != tt.want {
+			t.Errorf("ToConnectError(%v) code = %v, want %v", tt.err, got, tt.want)
+		}
+		if tt.want != connect.CodeUnknown && owl.FromConnectCode(tt.want) != owl.CodeFromError(tt.err) {
+			t.Errorf("FromConnectCode(%v) does not round-trip", tt.want)
+		}
+	}
+
+	ce := ToConnectError(owl.Problem(owl.Invalid,
+		owl.WithMsg("email failed regex"),
+		owl.WithSafeMsg("invalid email"),
+		owl.WithSafeDetails(map[string]any{"field": "email"}),
+	))
+	if ce.Message() != "invalid email" {
+		t.Errorf("Expected the public message, got %q", ce.Message())
+	}
+	hydrated, ok := owl.AsError(FromConnectError(ce))
+	if !ok || hydrated.Code != owl.Invalid || hydrated.SafeDetails["field"] != "email" {
+		t.Errorf("Expected code and safe details to survive, got %+v", hydrated)
+	}
+
+	if ToConnectError(nil) != nil {
+		t.Error("Expected nil for a nil error")
+	}
+}
+
+func TestGRPCFactory_ConnectInterceptor(t *testing.T) {
+	logger := owltest.NewLogger()
+	monitor := owltest.NewMonitor()
+	interceptor := NewGRPCFactory(logger, monitor).ConnectInterceptor()
+
+	const procedure = "/items.v1.Items/Get"
+	mux := http.NewServeMux()
+	mux.Handle(procedure, connect.NewUnaryHandler(procedure,
+		func(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+			return nil, owl.Problem(owl.NotFound, owl.WithMsg("item 7 missing"), owl.WithSafeMsg("item not found"))
+		},
+		connect.WithInterceptors(interceptor),
+	))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := connect.NewClient[emptypb.Empty, emptypb.Empty](srv.Client(), srv.URL+procedure,
+		connect.WithInterceptors(interceptor))
+	_, err := client.CallUnary(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+
+	if connect.CodeOf(err) != connect.CodeNotFound {
+		t.Errorf("Expected NotFound on the wire, got %v", err)
+	}
+	if e, ok := owl.AsError(err); !ok || e.Code != owl.NotFound || e.Msg != "item not found" {
+		t.Errorf("Expected the client error hydrated into owl NotFound, got %v", err)
+	}
+	if logger.Find("item 7 missing") == nil {
+		t.Error("Expected the internal message to be logged")
+	}
+	if got := monitor.GetCounterWith("connect_requests_total", owl.Attr("method", procedure), owl.Attr("code", "not_found")); got != 1 {
+		t.Errorf("Expected 1 not_found request, got %v", got)
+	}
+}
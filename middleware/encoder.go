@@ -0,0 +1,179 @@
+package middleware
+
+import (
+	"encoding/xml"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/myuser/owl"
+)
+
+// RegisterErrorEncoder registers enc for responses to requests accepting mediaType
+// (e.g. "application/xml"). JSON ("application/json" and "application/problem+json")
+// is registered by default, and used when no registered type is acceptable.
+// Encoders must only expose public fields (SafeMsg, never Msg).
+func (f *HTTPFactory) RegisterErrorEncoder(mediaType string, enc ErrorEncoder) {
+	f.encodersMu.Lock()
+	defer f.encodersMu.Unlock()
+	f.encoders[strings.ToLower(mediaType)] = enc
+}
+
+// negotiateErrorEncoder picks an encoder based on the request Accept header.
+func (f *HTTPFactory) negotiateErrorEncoder(w http.ResponseWriter, r *http.Request, err error) {
+	f.encodersMu.RLock()
+	types := make([]string, 0, len(f.encoders))
+	for mt := range f.encoders {
+		types = append(types, mt)
+	}
+	// JSON first, so wildcards resolve to it, then the others in a stable order
+	sort.Slice(types, func(i, j int) bool {
+		if ri, rj := jsonRank(types[i]), jsonRank(types[j]); ri != rj {
+			return ri < rj
+		}
+		return types[i] < types[j]
+	})
+	enc := f.encoders[negotiate(parseAccept(r.Header.Get("Accept")), types)]
+	f.encodersMu.RUnlock()
+
+	if enc == nil {
+		enc = defaultErrorEncoder
+	}
+	enc(w, r, err)
+}
+
+func jsonRank(mediaType string) int {
+	switch mediaType {
+	case "application/json":
+		return 0
+	case "application/problem+json":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// acceptRange is a media range of an Accept header with its quality.
+type acceptRange struct {
+	mediaType string // "type/subtype", "type/*" or "*/*"
+	q         float64
+}
+
+// specificity ranks exact types over "type/*" over "*/*".
+func (ar acceptRange) specificity() int {
+	switch {
+	case ar.mediaType == "*/*":
+		return 0
+	case strings.HasSuffix(ar.mediaType, "/*"):
+		return 1
+	default:
+		return 2
+	}
+}
+
+func (ar acceptRange) matches(mediaType string) bool {
+	switch ar.specificity() {
+	case 0:
+		return true
+	case 1:
+		return strings.HasPrefix(mediaType, strings.TrimSuffix(ar.mediaType, "*"))
+	default:
+		return ar.mediaType == mediaType
+	}
+}
+
+// parseAccept parses an Accept header into media ranges ordered by
+// preference. Ranges with q=0 are kept, at the end, as they exclude types.
+func parseAccept(accept string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		ranges = append(ranges, acceptRange{mediaType: mt, q: q})
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].q > ranges[j].q
+	})
+	return ranges
+}
+
+// negotiate returns the first of types, in ranges order, that the Accept
+// ranges allow, or "" when none is. A type's quality is that of the most
+// specific range matching it, so "application/xml;q=0" excludes XML even
+// when "*/*" is accepted.
+func negotiate(ranges []acceptRange, types []string) string {
+	for i, ar := range ranges {
+		if ar.q <= 0 {
+			break
+		}
+		for _, mt := range types {
+			if ar.matches(mt) && bestRange(ranges, mt) == i {
+				return mt
+			}
+		}
+	}
+	return ""
+}
+
+// bestRange returns the index of the most specific range matching mediaType,
+// the earliest among equally specific ones, or -1.
+func bestRange(ranges []acceptRange, mediaType string) int {
+	best := -1
+	for i, ar := range ranges {
+		if ar.matches(mediaType) && (best < 0 || ar.specificity() > ranges[best].specificity()) {
+			best = i
+		}
+	}
+	return best
+}
+
+// publicError returns the client-safe code and message for err.
+func publicError(err error) (code, msg string) {
+	if obsErr, ok := asPublicError(err); ok {
+		return obsErr.Code.String(), obsErr.PublicMessage()
+	}
+	return "INTERNAL", "Internal Server Error"
+}
+
+// asPublicError returns the *owl.Error in err's chain. Plain context errors,
+// which owl classifies as DeadlineExceeded or Canceled, get a bare *owl.Error
+// of that code so their response body matches the status.
+func asPublicError(err error) (*owl.Error, bool) {
+	if obsErr, ok := owl.AsError(err); ok {
+		return obsErr, true
+	}
+	if code := owl.CodeFromError(err); code != owl.Internal {
+		return owl.Problem(code), true
+	}
+	return nil, false
+}
+
+// XMLErrorEncoder writes the public code and message as an XML document.
+func XMLErrorEncoder(w http.ResponseWriter, r *http.Request, err error) {
+	code, msg := publicError(err)
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(owl.ToHTTPStatus(err))
+	_ = xml.NewEncoder(w).Encode(struct {
+		XMLName xml.Name `xml:"error"`
+		Code    string   `xml:"code"`
+		Message string   `xml:"message"`
+	}{Code: code, Message: msg})
+}
+
+// TextErrorEncoder writes the public code and message as a single line of plain text.
+func TextErrorEncoder(w http.ResponseWriter, r *http.Request, err error) {
+	code, msg := publicError(err)
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(owl.ToHTTPStatus(err))
+	_, _ = w.Write([]byte(code + ": " + msg + "\n"))
+}
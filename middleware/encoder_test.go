@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/myuser/owl"
+)
+
+func TestParseAccept(t *testing.T) {
+	got := parseAccept("text/html, application/xml;q=0.9, text/plain;q=0.95, */*;q=0")
+	want := []acceptRange{{"text/html", 1}, {"text/plain", 0.95}, {"application/xml", 0.9}, {"*/*", 0}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseAccept = %v, want %v", got, want)
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	types := []string{"application/json", "application/problem+json", "application/xml", "text/plain"}
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"application/json, application/xml;q=0.5", "application/json"},
+		{"application/xml, application/json;q=0.5", "application/xml"},
+		{"*/*", "application/json"},
+		{"text/*", "text/plain"},
+		{"application/json;q=0, */*", "application/problem+json"},
+		{"text/plain;q=0, text/*", ""},
+		{"*/*;q=0.1, text/plain;q=0.5", "text/plain"},
+		{"text/csv", ""},
+	}
+	for _, tt := range tests {
+		if got := negotiate(parseAccept(tt.accept), types); got != tt.want {
+			t.Errorf("negotiate(%q) = %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestHTTPFactory_ContentNegotiation(t *testing.T) {
+	f := NewHTTPFactory(nil, nil)
+	f.RegisterErrorEncoder("application/xml", XMLErrorEncoder)
+	f.RegisterErrorEncoder("text/plain", TextErrorEncoder)
+
+	h := f.Wrap(func(w http.ResponseWriter, r *http.Request) error {
+		return owl.Problem(owl.NotFound, owl.WithMsg("row 42 missing in db"), owl.WithSafeMsg("item not found"))
+	})
+
+	tests := []struct {
+		accept      string
+		contentType string
+		body        string
+	}{
+		{"application/xml", "application/xml", "<error><code>NOT_FOUND</code><message>item not found</message></error>"},
+		{"text/plain", "text/plain; charset=utf-8", "NOT_FOUND: item not found\n"},
+		{"text/csv", "application/json", `"message":"item not found"`},
+		{"application/json, application/xml;q=0.5", "application/json", `"code":"NOT_FOUND"`},
+		{"*/*", "application/json", `"code":"NOT_FOUND"`},
+		{"", "application/json", `"code":"NOT_FOUND"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			if w.Code != http.StatusNotFound {
+				t.Errorf("Expected 404, got %d", w.Code)
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.contentType)
+			}
+			body := w.Body.String()
+			if !strings.Contains(body, tt.body) {
+				t.Errorf("Body %q does not contain %q", body, tt.body)
+			}
+			if strings.Contains(body, "row 42") {
+				t.Errorf("Internal message leaked: %q", body)
+			}
+		})
+	}
+}
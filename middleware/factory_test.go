@@ -1,8 +1,12 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"github.com/myuser/owl"
 )
 
 func TestNewHTTPFactory_Options(t *testing.T) {
@@ -38,3 +42,25 @@ func TestNewHTTPClient_NilSafety(t *testing.T) {
 		t.Error("Expected DefaultTransport fallback")
 	}
 }
+
+func TestDefaultErrorEncoder_ContentType(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"plain owl error", owl.Problem(owl.NotFound), "application/json"},
+		{"problem details", owl.Problem(owl.NotFound, owl.WithType("https://example.com/probs/missing")), "application/problem+json"},
+		{"generic error", errors.New("boom"), "application/json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			defaultErrorEncoder(w, httptest.NewRequest("GET", "/", nil), tt.err)
+			if got := w.Header().Get("Content-Type"); got != tt.want {
+				t.Errorf("Content-Type = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,36 +2,63 @@ package middleware
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/myuser/owl"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
 // GRPCFactory allows injecting dependencies.
 type GRPCFactory struct {
-	logger  owl.Logger
-	monitor owl.Monitor
+	logger        owl.Logger
+	monitor       owl.Monitor
+	errorMapper   func(error) *owl.Error
+	baggageLabels []string // See WithGRPCBaggageMetricLabels
 }
 
 // NewGRPCFactory creates a new factory.
-func NewGRPCFactory(l owl.Logger, m owl.Monitor) *GRPCFactory {
+func NewGRPCFactory(l owl.Logger, m owl.Monitor, opts ...func(*GRPCFactory)) *GRPCFactory {
 	if l == nil {
 		l = owl.NoOpLogger{}
 	}
 	if m == nil {
 		m = owl.NoOpMonitor{}
 	}
-	return &GRPCFactory{logger: l, monitor: m}
+	f := &GRPCFactory{logger: l, monitor: m}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// WithGRPCErrorMapper sets fn to translate errors returned by handlers before
+// they are converted to a gRPC status. See WithErrorMapper.
+func WithGRPCErrorMapper(fn func(error) *owl.Error) func(*GRPCFactory) {
+	return func(f *GRPCFactory) {
+		f.errorMapper = fn
+	}
+}
+
+// WithGRPCBaggageMetricLabels adds the named baggage members as attributes on
+// the request metrics. See WithBaggageMetricLabels.
+func WithGRPCBaggageMetricLabels(keys ...string) func(*GRPCFactory) {
+	return func(f *GRPCFactory) {
+		f.baggageLabels = keys
+	}
 }
 
 // UnaryServerInterceptor returns a new interceptor.
 func (f *GRPCFactory) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
 	reqCount := f.monitor.Counter("grpc_requests_total")
 	reqLatency := f.monitor.Histogram("grpc_request_duration_seconds")
+	inFlight := f.monitor.UpDownCounter("grpc_requests_in_flight")
 
 	return func(
 		ctx context.Context,
@@ -39,11 +66,17 @@ func (f *GRPCFactory) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
+		method := owl.Attr("method", owl.NormalizeGRPCMethod(info.FullMethod))
+		inFlight.Add(ctx, 1, method)
+		defer inFlight.Add(ctx, -1, method)
+
 		// 1. Trace Extraction
 		md, ok := metadata.FromIncomingContext(ctx)
 		if ok {
 			ctx = otel.GetTextMapPropagator().Extract(ctx, &metadataSupplier{md})
 		}
+		ctx, endSpan := startServerSpan(ctx, "grpc", info.FullMethod)
+		ctx = f.requestScope(ctx, md, info.FullMethod)
 
 		start := time.Now()
 
@@ -51,60 +84,170 @@ func (f *GRPCFactory) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
 		resp, err := handler(ctx, req)
 		duration := time.Since(start).Seconds()
 
-		// 3. Match code
-		codeStr := "OK"
-		if err != nil {
-			if s, ok := status.FromError(err); ok {
-				codeStr = s.Code().String()
-			} else {
-				codeStr = "UNKNOWN"
-			}
+		err = mapError(f.errorMapper, err)
+		wireErr := f.finish(ctx, info.FullMethod, duration, err, reqCount, reqLatency)
+		endServerSpan(ctx, endSpan, err, wireErr)
+		if wireErr != nil {
+			return nil, wireErr
 		}
+		return resp, nil
+	}
+}
 
-		// 4. Metrics
-		reqCount.Inc(ctx,
-			owl.Attr("method", info.FullMethod),
-			owl.Attr("code", codeStr),
-		)
-		reqLatency.Record(ctx, duration,
-			owl.Attr("method", info.FullMethod),
-			owl.Attr("code", codeStr),
-		)
+// StreamServerInterceptor returns a new stream interceptor.
+// The whole stream is timed and reported like a unary call.
+func (f *GRPCFactory) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	reqCount := f.monitor.Counter("grpc_requests_total")
+	reqLatency := f.monitor.Histogram("grpc_request_duration_seconds")
+	inFlight := f.monitor.UpDownCounter("grpc_requests_in_flight")
 
-		// 5. Error Handling
-		if err != nil {
-			// Convert to gRPC Status
-			gst := owl.ToGRPCStatus(err)
-
-			// Log internal error with full details
-			// If it's an ObsError, we have rich details
-			var obsErr *owl.Error
-			if e, ok := err.(*owl.Error); ok {
-				obsErr = e
-				f.logger.Error(ctx, obsErr.Msg, obsErr.Err,
-					"code", gst.Code().String(),
-					"duration", duration,
-					"method", info.FullMethod,
-				)
-			} else {
-				f.logger.Error(ctx, "grpc_request_failed", err,
-					"code", gst.Code().String(),
-					"duration", duration,
-					"method", info.FullMethod,
-				)
-			}
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx := ss.Context()
+		method := owl.Attr("method", owl.NormalizeGRPCMethod(info.FullMethod))
+		inFlight.Add(ctx, 1, method)
+		defer inFlight.Add(ctx, -1, method)
 
-			// Return the converted status error (which contains SafeMsg)
-			return nil, gst.Err()
+		// 1. Trace Extraction
+		md, ok := metadata.FromIncomingContext(ctx)
+		if ok {
+			ctx = otel.GetTextMapPropagator().Extract(ctx, &metadataSupplier{md})
 		}
+		ctx, endSpan := startServerSpan(ctx, "grpc", info.FullMethod)
+		ctx = f.requestScope(ctx, md, info.FullMethod)
+
+		start := time.Now()
+
+		// 2. Execution
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		duration := time.Since(start).Seconds()
 
-		// 4. Success Logging
-		f.logger.Info(ctx, "grpc_request_success",
-			"code", "OK",
-			"duration", duration,
-			"method", info.FullMethod,
+		err = mapError(f.errorMapper, err)
+		wireErr := f.finish(ctx, info.FullMethod, duration, err, reqCount, reqLatency)
+		endServerSpan(ctx, endSpan, err, wireErr)
+		return wireErr
+	}
+}
+
+// requestIDMetadataKey is the incoming metadata key holding the caller's request ID.
+const requestIDMetadataKey = "x-request-id"
+
+// requestScope stores the request ID (the caller's if valid, see
+// validRequestID, or a new one) in ctx,
+// together with a logger bound with it and the method for owl.LoggerFromContext.
+func (f *GRPCFactory) requestScope(ctx context.Context, md metadata.MD, method string) context.Context {
+	requestID := ""
+	if ids := md.Get(requestIDMetadataKey); len(ids) > 0 {
+		requestID = ids[0]
+	}
+	if !validRequestID(requestID) {
+		requestID = newRequestID()
+	}
+	ctx = owl.WithRequestID(ctx, requestID)
+	return owl.ContextWithLogger(ctx, f.logger.With("request_id", requestID, "method", method))
+}
+
+// startServerSpan starts a server span named after the normalized full method
+// ("/package.Service/Method"), a child of the extracted trace context.
+// system is the rpc.system attribute, "grpc" or "connect_rpc".
+func startServerSpan(ctx context.Context, system, fullMethod string) (context.Context, func(*error)) {
+	attrs := []attribute.KeyValue{attribute.String("rpc.system", system)}
+	if service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/"); ok {
+		attrs = append(attrs,
+			attribute.String("rpc.service", service),
+			attribute.String("rpc.method", method),
 		)
+	}
+	return owl.Start(ctx, owl.NormalizeGRPCMethod(fullMethod),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attrs...),
+	)
+}
 
-		return resp, nil
+// endServerSpan records the gRPC status code of wireErr, the status sent to
+// the client, and ends the span with err, the handler's (mapped) error, so the
+// span error filter and owl error fields apply to it.
+func endServerSpan(ctx context.Context, end func(*error), err, wireErr error) {
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Int("rpc.grpc.status_code", int(status.Code(wireErr))),
+	)
+	end(&err)
+}
+
+// serverStream overrides the context of a grpc.ServerStream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// finish records metrics and logs for a completed call, and converts err
+// (already passed through the error mapper) into the status error returned to
+// the client. Metrics are labelled with the normalized method, logs keep the
+// raw one.
+func (f *GRPCFactory) finish(ctx context.Context, method string, duration float64, err error, reqCount owl.Counter, reqLatency owl.Histogram) error {
+	// 3. Match code
+	codeStr := "OK"
+	if err != nil {
+		if s, ok := status.FromError(err); ok {
+			codeStr = s.Code().String()
+		} else if c := owl.ToGRPCStatus(err).Code(); c != codes.Unknown {
+			// owl errors, and context errors classified by owl
+			codeStr = c.String()
+		} else {
+			codeStr = "UNKNOWN"
+		}
 	}
+
+	// 4. Metrics
+	attrs := append([]owl.Attribute{
+		owl.Attr("method", owl.NormalizeGRPCMethod(method)),
+		owl.Attr("code", codeStr),
+	}, baggageAttrs(ctx, f.baggageLabels)...)
+	reqCount.Inc(ctx, attrs...)
+	reqLatency.Record(ctx, duration, attrs...)
+
+	// 5. Error Handling
+	if err != nil {
+		// Convert to gRPC Status
+		gst := owl.ToGRPCStatus(err)
+
+		// Log internal error with full details
+		// If it's an ObsError, we have rich details
+		if obsErr, ok := owl.AsError(err); ok {
+			fields := []any{
+				"code", gst.Code().String(),
+				"duration", duration,
+				"method", method,
+			}
+			fields = append(fields, errorLogFields(obsErr)...)
+			f.logger.Error(ctx, obsErr.Msg, obsErr.Err, fields...)
+		} else {
+			f.logger.Error(ctx, "grpc_request_failed", err,
+				"code", gst.Code().String(),
+				"duration", duration,
+				"method", method,
+				"error_code", owl.CodeFromError(err).String(),
+			)
+		}
+
+		// Return the converted status error (which contains SafeMsg)
+		return gst.Err()
+	}
+
+	// 4. Success Logging
+	f.logger.Info(ctx, "grpc_request_success",
+		"code", "OK",
+		"duration", duration,
+		"method", method,
+	)
+
+	return nil
 }
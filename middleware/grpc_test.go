@@ -0,0 +1,291 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/myuser/owl"
+	"github.com/myuser/owl/owltest"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeServerStream is a grpc.ServerStream that only provides a context.
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestGRPCFactory_StreamServerInterceptor(t *testing.T) {
+	logger := owltest.NewLogger()
+	monitor := owltest.NewMonitor()
+	interceptor := NewGRPCFactory(logger, monitor).StreamServerInterceptor()
+	info := &grpc.StreamServerInfo{FullMethod: "/items.Items/Watch"}
+
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		if stream.Context() == nil {
+			t.Error("Expected stream context")
+		}
+		return owl.Problem(owl.NotFound, owl.WithMsg("item 42 missing"), owl.WithSafeMsg("item not found"))
+	}
+
+	err := interceptor(nil, &fakeServerStream{ctx: context.Background()}, info, handler)
+
+	st, ok := status.FromError(err)
+	if !ok {
+		t.Fatalf("Expected status error, got %T", err)
+	}
+	if st.Code() != codes.NotFound || st.Message() != "item not found" {
+		t.Errorf("Unexpected status %v: %q", st.Code(), st.Message())
+	}
+	if logger.Find("item 42 missing") == nil {
+		t.Error("Expected internal message to be logged")
+	}
+	if got := monitor.GetCounter("grpc_requests_total"); got != 1 {
+		t.Errorf("Expected 1 request, got %v", got)
+	}
+	if n := monitor.HistogramCount("grpc_request_duration_seconds"); n != 1 {
+		t.Errorf("Expected 1 latency sample, got %d", n)
+	}
+
+	// Success path
+	err = interceptor(nil, &fakeServerStream{ctx: context.Background()}, info, func(srv interface{}, stream grpc.ServerStream) error {
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Expected nil error, got %v", err)
+	}
+	if got := monitor.GetCounterWith("grpc_requests_total", owl.Attr("method", info.FullMethod), owl.Attr("code", "OK")); got != 1 {
+		t.Errorf("Expected 1 OK request, got %v", got)
+	}
+}
+
+func TestGRPCFactory_InFlight(t *testing.T) {
+	monitor := owltest.NewMonitor()
+	interceptor := NewGRPCFactory(nil, monitor).UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/items.Items/Get"}
+
+	var during float64
+	_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		during = monitor.GetGauge("grpc_requests_in_flight")
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if during != 1 {
+		t.Errorf("Expected 1 in-flight request during handler, got %v", during)
+	}
+	if after := monitor.GetGauge("grpc_requests_in_flight"); after != 0 {
+		t.Errorf("Expected 0 in-flight requests after handler, got %v", after)
+	}
+}
+
+func TestGRPCFactory_MalformedMethodLabel(t *testing.T) {
+	logger := owltest.NewLogger()
+	monitor := owltest.NewMonitor()
+	interceptor := NewGRPCFactory(logger, monitor).UnaryServerInterceptor()
+	ok := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+
+	for _, m := range []string{"/items.Items/Get?id=1", "/items.Items/Get?id=2", "garbage"} {
+		if _, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: m}, ok); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if got := monitor.GetCounterWith("grpc_requests_total", owl.Attr("method", owl.UnknownGRPCMethod), owl.Attr("code", "OK")); got != 3 {
+		t.Errorf("Expected malformed methods to collapse into one series, got %v", got)
+	}
+	if v, _ := logger.LastEntry().Field("method"); v != "garbage" {
+		t.Errorf("Expected logs to keep the raw method, got %v", v)
+	}
+}
+
+func TestGRPCFactory_ServerSpan(t *testing.T) {
+	sr := withSpanRecorder(t)
+	interceptor := NewGRPCFactory(nil, nil).UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/items.Items/Get"}
+
+	_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		if !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+			t.Error("Expected handler context to carry the server span")
+		}
+		return nil, owl.Problem(owl.NotFound)
+	})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("Expected NotFound, got %v", err)
+	}
+
+	spans := sr.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != info.FullMethod {
+		t.Errorf("Expected span name %q, got %q", info.FullMethod, span.Name())
+	}
+	if span.SpanKind() != trace.SpanKindServer {
+		t.Errorf("Expected server span, got %v", span.SpanKind())
+	}
+	if span.Status().Code != otelcodes.Error {
+		t.Errorf("Expected error status, got %v", span.Status())
+	}
+	if got := spanAttr(span, "rpc.method").AsString(); got != "Get" {
+		t.Errorf("Expected rpc.method Get, got %q", got)
+	}
+	if got := spanAttr(span, "rpc.grpc.status_code").AsInt64(); got != int64(codes.NotFound) {
+		t.Errorf("Expected rpc.grpc.status_code %d, got %d", codes.NotFound, got)
+	}
+
+	// The span error filter sees the owl error, not the converted status
+	owl.SetSpanErrorFilter(func(err error) bool { return !errors.Is(err, owl.NotFound) })
+	defer owl.SetSpanErrorFilter(nil)
+	interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, owl.Problem(owl.NotFound)
+	})
+	span = sr.Ended()[1]
+	if span.Status().Code == otelcodes.Error {
+		t.Errorf("Expected a filtered NotFound not to mark the span, got %v", span.Status())
+	}
+	if got := spanAttr(span, "rpc.grpc.status_code").AsInt64(); got != int64(codes.NotFound) {
+		t.Errorf("Expected rpc.grpc.status_code %d, got %d", codes.NotFound, got)
+	}
+}
+
+func TestGRPCFactory_WrappedOwlError(t *testing.T) {
+	logger := owltest.NewLogger()
+	interceptor := NewGRPCFactory(logger, nil).UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/items.Items/Get"}
+
+	_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, fmt.Errorf("get: %w", owl.Problem(owl.NotFound, owl.WithMsg("item 7 missing"), owl.WithSafeMsg("item not found")))
+	})
+
+	st, _ := status.FromError(err)
+	if st.Code() != codes.NotFound || st.Message() != "item not found" {
+		t.Errorf("Unexpected status %v: %q", st.Code(), st.Message())
+	}
+	if logger.Find("item 7 missing") == nil {
+		t.Error("Expected the wrapped owl error's message to be logged")
+	}
+}
+
+func TestGRPCFactory_ContextLogger(t *testing.T) {
+	logger := owltest.NewLogger()
+	interceptor := NewGRPCFactory(logger, nil).UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/items.Items/Get"}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-9"))
+	_, _ = interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		owl.LoggerFromContext(ctx).Info(ctx, "loading item")
+		return nil, nil
+	})
+
+	entry := logger.Find("loading item")
+	if entry == nil {
+		t.Fatal("Expected the handler to log through the request logger")
+	}
+	if id, _ := entry.Field("request_id"); id != "req-9" {
+		t.Errorf("Expected request_id from metadata, got %v", entry.Args)
+	}
+	if method, _ := entry.Field("method"); method != "/items.Items/Get" {
+		t.Errorf("Expected bound method, got %v", entry.Args)
+	}
+
+	// Unsafe IDs are replaced
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req 9\nforged"))
+	_, _ = interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		if id := owl.RequestIDFromContext(ctx); len(id) != 36 {
+			t.Errorf("Expected a generated request ID, got %q", id)
+		}
+		return nil, nil
+	})
+}
+
+func TestGRPCFactory_ErrorMapper(t *testing.T) {
+	errNoRows := errors.New("no rows in result set")
+	interceptor := NewGRPCFactory(nil, nil, WithGRPCErrorMapper(func(err error) *owl.Error {
+		if errors.Is(err, errNoRows) {
+			return owl.Problem(owl.NotFound, owl.WithErr(err))
+		}
+		return nil
+	})).UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/items.Items/Get"}
+
+	_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, fmt.Errorf("get item: %w", errNoRows)
+	})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected mapped NotFound, got %v", err)
+	}
+
+	_, err = interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errors.New("boom")
+	})
+	if status.Code(err) != codes.Unknown {
+		t.Errorf("Expected unmapped error to stay Unknown, got %v", err)
+	}
+}
+
+func TestGRPCFactory_BaggageMetricLabels(t *testing.T) {
+	monitor := owltest.NewMonitor()
+	interceptor := NewGRPCFactory(nil, monitor, WithGRPCBaggageMetricLabels("tenant_id")).UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/items.Items/Get"}
+
+	ctx := owl.SetBaggage(context.Background(), "tenant_id", "acme")
+	if _, err := interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	got := monitor.GetCounterWith("grpc_requests_total",
+		owl.Attr("method", info.FullMethod),
+		owl.Attr("code", "OK"),
+		owl.Attr("tenant_id", "acme"),
+	)
+	if got != 1 {
+		t.Errorf("Expected 1 request labelled with tenant_id, got %v", got)
+	}
+}
+
+func TestGRPCFactory_ErrorLogFields(t *testing.T) {
+	logger := owltest.NewLogger()
+	interceptor := NewGRPCFactory(logger, nil).UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/items.Items/Get"}
+
+	interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, owl.Problem(owl.Internal, owl.WithMsg("store unreachable"), owl.WithOp("Items.Get"))
+	})
+	entry := logger.Find("store unreachable")
+	if entry == nil {
+		t.Fatal("Expected error log")
+	}
+	code, _ := entry.Field("error_code")
+	op, _ := entry.Field("op")
+	if code != "INTERNAL" || op != "Items.Get" {
+		t.Errorf("Expected error_code and op in log args, got %v", entry.Args)
+	}
+	if grpcCode, _ := entry.Field("code"); grpcCode != codes.Internal.String() {
+		t.Errorf("Expected the gRPC code to stay in code, got %v", grpcCode)
+	}
+
+	// Plain errors are classified too, so alerts on error_code see them
+	interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errors.New("boom")
+	})
+	if code, _ := logger.Find("grpc_request_failed").Field("error_code"); code != "INTERNAL" {
+		t.Errorf("Expected error_code INTERNAL for a plain error, got %v", code)
+	}
+}
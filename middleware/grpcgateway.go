@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/myuser/owl"
+	"google.golang.org/grpc/status"
+)
+
+// GatewayErrorHandler is a grpc-gateway runtime.ErrorHandlerFunc that writes
+// errors in the same JSON shape as HTTPFactory, so clients see identical
+// errors whether a service is served natively or through the gateway.
+// The gRPC status is converted back into an *owl.Error, keeping its code,
+// message and the SafeDetails attached by owl.ToGRPCStatus. The gateway's own
+// routing errors (*runtime.HTTPStatusError, e.g. 405 for an unsupported method)
+// keep their HTTP status.
+func GatewayErrorHandler(ctx context.Context, mux *runtime.ServeMux, m runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	var httpErr *runtime.HTTPStatusError
+	if errors.As(err, &httpErr) {
+		opts := []owl.Option{owl.WithHTTPStatus(httpErr.HTTPStatus), owl.WithErr(httpErr.Err)}
+		if st, ok := status.FromError(httpErr.Err); ok {
+			opts = append(opts, owl.WithMsg(st.Message()), owl.WithSafeMsg(st.Message()))
+		}
+		err = owl.Problem(owl.FromHTTPStatus(httpErr.HTTPStatus), opts...)
+	} else if st, ok := status.FromError(err); ok {
+		err = hydrateGRPCError(err)
+		if obsErr, ok := owl.AsError(err); ok {
+			// The status message is the server's public message
+			obsErr.SafeMsg = st.Message()
+		}
+	}
+	defaultErrorEncoder(w, r, err)
+}
+
+// WithGatewayErrorHandler returns a ServeMuxOption installing GatewayErrorHandler:
+//
+//	mux := runtime.NewServeMux(middleware.WithGatewayErrorHandler())
+func WithGatewayErrorHandler() runtime.ServeMuxOption {
+	return runtime.WithErrorHandler(GatewayErrorHandler)
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/myuser/owl"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGatewayErrorHandler(t *testing.T) {
+	// A status as sent by a server using owl's gRPC interceptors
+	st := owl.ToGRPCStatus(owl.Problem(owl.Invalid,
+		owl.WithMsg("email rejected by validator"),
+		owl.WithSafeMsg("invalid email"),
+		owl.WithSafeDetails(map[string]any{"field": "email"}),
+	))
+
+	mux := runtime.NewServeMux(WithGatewayErrorHandler())
+	w := httptest.NewRecorder()
+	GatewayErrorHandler(context.Background(), mux, &runtime.JSONPb{}, w, httptest.NewRequest("POST", "/v1/users", nil), st.Err())
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected application/json, got %q", ct)
+	}
+	var body map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Invalid JSON body %q: %v", w.Body.String(), err)
+	}
+	if body["code"] != "INVALID" || body["message"] != "invalid email" {
+		t.Errorf("Expected owl code and public message, got %v", body)
+	}
+	if details, _ := body["details"].(map[string]any); details["field"] != "email" {
+		t.Errorf("Expected safe details to survive the gateway, got %v", body)
+	}
+
+	// Errors without a status are obscured like in HTTPFactory
+	w = httptest.NewRecorder()
+	GatewayErrorHandler(context.Background(), mux, &runtime.JSONPb{}, w, httptest.NewRequest("GET", "/", nil), errors.New("dial failed"))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected 500, got %d", w.Code)
+	}
+	body = nil
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Invalid JSON body %q: %v", w.Body.String(), err)
+	}
+	if body["message"] != "Internal Server Error" {
+		t.Errorf("Expected generic message, got %v", body)
+	}
+
+	// Routing errors of the gateway itself keep their HTTP status
+	w = httptest.NewRecorder()
+	routingErr := &runtime.HTTPStatusError{
+		HTTPStatus: http.StatusMethodNotAllowed,
+		Err:        status.Error(codes.Unimplemented, "Method Not Allowed"),
+	}
+	GatewayErrorHandler(context.Background(), mux, &runtime.JSONPb{}, w, httptest.NewRequest("PATCH", "/v1/users", nil), routingErr)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405, got %d", w.Code)
+	}
+	body = nil
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Invalid JSON body %q: %v", w.Body.String(), err)
+	}
+	if body["code"] != "INVALID" || body["message"] != "Method Not Allowed" {
+		t.Errorf("Expected the routing error, got %v", body)
+	}
+}
@@ -1,15 +1,24 @@
 package middleware
 
 import (
+	"context"
+	"crypto/rand"
 	"encoding/json"
-	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"runtime/debug"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/myuser/owl"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // HTTPHandler is a signature that returns an error, allowing specific error handling.
@@ -18,16 +27,44 @@ type HTTPHandler func(w http.ResponseWriter, r *http.Request) error
 // responseWriter is a wrapper to capture the status code.
 type responseWriter struct {
 	http.ResponseWriter
-	status int
+	status      int
+	bytes       int64 // Body bytes written, including across Flush calls
+	wroteHeader bool
+	ctx         context.Context // Source of warnings to flush as headers
 }
 
 func (rw *responseWriter) WriteHeader(code int) {
+	if !rw.wroteHeader {
+		rw.wroteHeader = true
+		if rw.ctx != nil {
+			setWarningHeaders(rw.Header(), owl.Warnings(rw.ctx))
+		}
+	}
 	rw.status = code
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	n, err := rw.ResponseWriter.Write(b)
+	rw.bytes += int64(n)
+	return n, err
+}
+
+// setWarningHeaders adds one RFC 7234 style Warning header per warning.
+func setWarningHeaders(h http.Header, warnings []owl.Warning) {
+	for _, w := range warnings {
+		h.Add("Warning", "299 - "+strconv.Quote(w.Code+": "+w.Message))
+	}
+}
+
 // Flush implements http.Flusher interface to allow streaming.
 func (rw *responseWriter) Flush() {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
 	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
 		f.Flush()
 	}
@@ -38,9 +75,25 @@ type ErrorEncoder func(w http.ResponseWriter, r *http.Request, err error)
 
 // HTTPFactory allows injecting dependencies (Logger, Monitor) into the middleware.
 type HTTPFactory struct {
-	logger       owl.Logger
-	monitor      owl.Monitor
-	errorEncoder ErrorEncoder
+	logger          owl.Logger
+	monitor         owl.Monitor
+	errorEncoder    ErrorEncoder
+	tracing         bool
+	responseSize    bool
+	requestIDHeader string
+	routeResolver   func(*http.Request) string
+	skip            func(*http.Request) bool
+	middlewares     []HTTPMiddleware // See Use
+	debugErrors     bool
+	errorMapper     func(error) *owl.Error
+	baggageLabels   []string // See WithBaggageMetricLabels
+	accessLog       func(r *http.Request, status int, duration float64) []any
+	successLevel    slog.Level // See WithSuccessLogLevel
+	successLog      bool       // See WithAccessLogEnabled
+	traceHeader     bool       // See WithTraceResponseHeader
+
+	encodersMu sync.RWMutex
+	encoders   map[string]ErrorEncoder // Keyed by media type, see RegisterErrorEncoder
 }
 
 // NewHTTPFactory creates a factory for middlewares.
@@ -53,10 +106,18 @@ func NewHTTPFactory(l owl.Logger, m owl.Monitor, opts ...func(*HTTPFactory)) *HT
 	}
 
 	f := &HTTPFactory{
-		logger:       l,
-		monitor:      m,
-		errorEncoder: defaultErrorEncoder,
+		logger:          l,
+		monitor:         m,
+		tracing:         true,
+		requestIDHeader: "X-Request-ID",
+		successLevel:    slog.LevelInfo,
+		successLog:      true,
+		encoders: map[string]ErrorEncoder{
+			"application/json":         defaultErrorEncoder,
+			"application/problem+json": defaultErrorEncoder,
+		},
 	}
+	f.errorEncoder = f.negotiateErrorEncoder
 	for _, opt := range opts {
 		opt(f)
 	}
@@ -64,64 +125,365 @@ func NewHTTPFactory(l owl.Logger, m owl.Monitor, opts ...func(*HTTPFactory)) *HT
 }
 
 // WithErrorEncoder sets a custom error encoder.
+// It replaces content negotiation entirely.
 func WithErrorEncoder(enc ErrorEncoder) func(*HTTPFactory) {
 	return func(f *HTTPFactory) {
 		f.errorEncoder = enc
 	}
 }
 
+// WithTracing toggles the server span started for every request (default on).
+// Incoming trace context is extracted either way.
+func WithTracing(enabled bool) func(*HTTPFactory) {
+	return func(f *HTTPFactory) {
+		f.tracing = enabled
+	}
+}
+
+// WithTraceResponseHeader toggles echoing the W3C "traceparent" (and
+// "tracestate") of the request's span in the response headers (default off), so
+// a client such as curl can look up the trace of its request. Nothing is
+// written when the request has no valid span.
+func WithTraceResponseHeader(enabled bool) func(*HTTPFactory) {
+	return func(f *HTTPFactory) {
+		f.traceHeader = enabled
+	}
+}
+
+// WithResponseSizeMetric toggles the "http_response_size_bytes" histogram (default off).
+// The "bytes" log field is always recorded.
+func WithResponseSizeMetric(enabled bool) func(*HTTPFactory) {
+	return func(f *HTTPFactory) {
+		f.responseSize = enabled
+	}
+}
+
+// WithRequestIDHeader sets the header used to read and echo the request ID (default "X-Request-ID").
+func WithRequestIDHeader(name string) func(*HTTPFactory) {
+	return func(f *HTTPFactory) {
+		f.requestIDHeader = name
+	}
+}
+
+// WithRouteResolver sets how the "path" metric label and the http.route span
+// attribute are derived, e.g. the matched route template of a third-party router
+// ("/users/{id}"). It is called after the handler returns.
+//
+// By default the Go 1.22+ ServeMux pattern (r.Pattern) is used, falling back to
+// the raw URL path. Raw paths embed IDs, so every distinct URL becomes its own
+// time series: prefer a resolver when handlers are not routed by ServeMux.
+func WithRouteResolver(fn func(*http.Request) string) func(*HTTPFactory) {
+	return func(f *HTTPFactory) {
+		f.routeResolver = fn
+	}
+}
+
+// WithSkip excludes requests matching fn from logging, metrics and tracing.
+// The handler still runs, with panic recovery and error encoding.
+// It can be combined with WithSkipPaths; a request is skipped if any predicate matches.
+func WithSkip(fn func(*http.Request) bool) func(*HTTPFactory) {
+	return func(f *HTTPFactory) {
+		if prev := f.skip; prev != nil {
+			f.skip = func(r *http.Request) bool { return prev(r) || fn(r) }
+			return
+		}
+		f.skip = fn
+	}
+}
+
+// WithSkipPaths excludes requests whose URL path exactly matches one of paths
+// (e.g. "/healthz", "/metrics"). See WithSkip.
+func WithSkipPaths(paths ...string) func(*HTTPFactory) {
+	set := make(map[string]struct{}, len(paths))
+	for _, p := range paths {
+		set[p] = struct{}{}
+	}
+	return WithSkip(func(r *http.Request) bool {
+		_, ok := set[r.URL.Path]
+		return ok
+	})
+}
+
+// WithDebugErrors exposes internals in error responses (default off): the
+// recovered value and stack of a panic, and the message of errors that are not
+// *owl.Error. Only enable it for local development, never in production.
+func WithDebugErrors(enabled bool) func(*HTTPFactory) {
+	return func(f *HTTPFactory) {
+		f.debugErrors = enabled
+	}
+}
+
+// WithErrorMapper sets fn to translate errors returned by handlers before they
+// are encoded, logged and counted, e.g. sql.ErrNoRows to NotFound. If fn returns
+// nil the error is used unchanged. Wrap the original with owl.WithErr to keep it
+// in the logs:
+//
+//	middleware.WithErrorMapper(func(err error) *owl.Error {
+//		if errors.Is(err, sql.ErrNoRows) {
+//			return owl.Problem(owl.NotFound, owl.WithErr(err))
+//		}
+//		return nil
+//	})
+func WithErrorMapper(fn func(error) *owl.Error) func(*HTTPFactory) {
+	return func(f *HTTPFactory) {
+		f.errorMapper = fn
+	}
+}
+
+// WithBaggageMetricLabels adds the named baggage members (e.g. "tenant_id") as
+// attributes on the request metrics. Only the listed keys are read, so label
+// cardinality stays under the caller's control; a member missing from a request
+// is recorded as "" to keep the label set fixed.
+func WithBaggageMetricLabels(keys ...string) func(*HTTPFactory) {
+	return func(f *HTTPFactory) {
+		f.baggageLabels = keys
+	}
+}
+
+// WithAccessLogFields sets the key-value pairs logged for every request, e.g. to
+// use "http.method" and "url.path" instead of "method" and "path". The request
+// carries the request ID (owl.RequestIDFromContext); duration is in seconds.
+// Error details and warnings are still appended after these fields.
+//
+// By default status, duration, method, path, request_id and bytes are logged.
+func WithAccessLogFields(fn func(r *http.Request, status int, duration float64) []any) func(*HTTPFactory) {
+	return func(f *HTTPFactory) {
+		f.accessLog = fn
+	}
+}
+
+// WithSuccessLogLevel sets the level of the "request_success" access log
+// (default Info), e.g. slog.LevelDebug to quiet high-traffic services. Levels
+// below Info log at Debug, and Warn or above at Warn. Failed requests are
+// always logged at Error.
+func WithSuccessLogLevel(level slog.Level) func(*HTTPFactory) {
+	return func(f *HTTPFactory) {
+		f.successLevel = level
+	}
+}
+
+// WithAccessLogEnabled toggles the "request_success" access log (default on).
+// Failed requests and panics are logged either way.
+func WithAccessLogEnabled(enabled bool) func(*HTTPFactory) {
+	return func(f *HTTPFactory) {
+		f.successLog = enabled
+	}
+}
+
+// logSuccess writes the access log of a successful request at the configured level.
+func (f *HTTPFactory) logSuccess(ctx context.Context, fields []any) {
+	if !f.successLog {
+		return
+	}
+	switch {
+	case f.successLevel < slog.LevelInfo:
+		f.logger.Debug(ctx, "request_success", fields...)
+	case f.successLevel < slog.LevelWarn:
+		f.logger.Info(ctx, "request_success", fields...)
+	default:
+		f.logger.Warn(ctx, "request_success", fields...)
+	}
+}
+
+// accessLogFields returns the access log fields of a finished request.
+func (f *HTTPFactory) accessLogFields(r *http.Request, status int, duration float64, bytes int64) []any {
+	if f.accessLog != nil {
+		return f.accessLog(r, status, duration)
+	}
+	return []any{
+		"status", status,
+		"duration", duration,
+		"method", r.Method,
+		"path", r.URL.Path,
+		"request_id", owl.RequestIDFromContext(r.Context()),
+		"bytes", bytes,
+	}
+}
+
+// serveSkipped runs h without instrumentation. Panics are still recovered and logged.
+func (f *HTTPFactory) serveSkipped(w http.ResponseWriter, r *http.Request, h HTTPHandler) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			f.logger.Error(logContext(r.Context()), "panic recovered", nil, "panic", rec, "path", r.URL.Path)
+			f.writePanicResponse(w, rec)
+		}
+	}()
+	if err := h(w, r); err != nil {
+		f.encodeError(w, r, mapError(f.errorMapper, err))
+	}
+}
+
+// mapError applies mapper to err, keeping err if there is no mapper or it returns nil.
+func mapError(mapper func(error) *owl.Error, err error) error {
+	if mapper == nil {
+		return err
+	}
+	if mapped := mapper(err); mapped != nil {
+		return mapped
+	}
+	return err
+}
+
+// writePanicResponse writes the 500 returned after a recovered panic: generic,
+// or with the panic value and stack if WithDebugErrors is on.
+// It must be called from the deferred recover so the stack shows the panic site.
+func (f *HTTPFactory) writePanicResponse(w http.ResponseWriter, rec any) {
+	fields := owl.CurrentJSONFields()
+	body := map[string]string{
+		fields.Code:    "INTERNAL",
+		fields.Message: "Internal Server Error",
+	}
+	if f.debugErrors {
+		body["panic"] = fmt.Sprint(rec)
+		body["stack"] = string(debug.Stack())
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusInternalServerError)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// encodeError writes err with the error encoder. With WithDebugErrors, errors
+// that are not *owl.Error are sent as Internal with their own message instead
+// of being obscured.
+func (f *HTTPFactory) encodeError(w http.ResponseWriter, r *http.Request, err error) {
+	if f.debugErrors {
+		if _, ok := owl.AsError(err); !ok {
+			err = owl.Problem(owl.Internal, owl.WithSafeMsg(err.Error()), owl.WithErr(err))
+		}
+	}
+	f.errorEncoder(w, r, err)
+}
+
+// route returns the low-cardinality route of r for metrics and spans.
+func (f *HTTPFactory) route(r *http.Request) string {
+	if f.routeResolver != nil {
+		if rt := f.routeResolver(r); rt != "" {
+			return rt
+		}
+	}
+	return route(r)
+}
+
 // defaultErrorEncoder writes JSON responses.
 func defaultErrorEncoder(w http.ResponseWriter, r *http.Request, err error) {
 	status := owl.ToHTTPStatus(err)
-	w.Header().Set("Content-Type", "application/json")
+
+	obsErr, isObsErr := asPublicError(err)
+
+	if isObsErr && obsErr.IsProblemDetails() {
+		w.Header().Set("Content-Type", "application/problem+json")
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+	}
 	w.WriteHeader(status)
 
-	var obsErr *owl.Error
-	if errors.As(err, &obsErr) {
+	if isObsErr {
 		// Marshal semantic error
 		_ = json.NewEncoder(w).Encode(obsErr)
 	} else {
 		// Obscure internal errors
+		fields := owl.CurrentJSONFields()
 		_ = json.NewEncoder(w).Encode(map[string]string{
-			"code":    "INTERNAL",
-			"message": "Internal Server Error",
+			fields.Code:    "INTERNAL",
+			fields.Message: "Internal Server Error",
 		})
 	}
 }
 
+// WrapNamed is like Wrap but stores op as the current operation in the request
+// context, so errors built with owl.WithContext get their Op filled automatically.
+func (f *HTTPFactory) WrapNamed(op string, h HTTPHandler) http.Handler {
+	return f.Wrap(func(w http.ResponseWriter, r *http.Request) error {
+		return h(w, r.WithContext(owl.WithOperation(r.Context(), op)))
+	})
+}
+
 // Wrap wraps a custom HTTPHandler and converts it to standard http.Handler.
 func (f *HTTPFactory) Wrap(h HTTPHandler) http.Handler {
 	// Pre-allocate metrics
 	reqCount := f.monitor.Counter("http_requests_total")
 	reqLatency := f.monitor.Histogram("http_request_duration_seconds")
+	inFlight := f.monitor.UpDownCounter("http_requests_in_flight")
+	h = Chain(f.middlewares...)(h)
+	var respSize owl.Histogram
+	if f.responseSize {
+		respSize = f.monitor.Histogram("http_response_size_bytes", owl.WithUnit("By"))
+	}
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if f.skip != nil && f.skip(r) {
+			f.serveSkipped(w, r, h)
+			return
+		}
+
 		start := time.Now()
 
+		inFlight.Add(r.Context(), 1)
+		defer inFlight.Add(r.Context(), -1)
+
 		// 1. Trace Extraction
 		// Extract trace context from headers and inject into request context
 		ctx := r.Context()
 		ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(r.Header))
+
+		// Server span, child of the extracted context
+		var endSpan func(*error)
+		if f.tracing {
+			ctx, endSpan = owl.Start(ctx, spanName(r),
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("http.method", r.Method),
+				),
+			)
+		}
+
+		// Request ID: reuse the caller's or generate one, and echo it back
+		requestID := r.Header.Get(f.requestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+		ctx = owl.WithRequestID(ctx, requestID)
+		w.Header().Set(f.requestIDHeader, requestID)
+
+		// Echo the current span so callers can find the trace of their request
+		if f.traceHeader {
+			propagation.TraceContext{}.Inject(ctx, propagation.HeaderCarrier(w.Header()))
+		}
+
+		// Handlers logging through owl.LoggerFromContext get the request ID for free
+		ctx = owl.ContextWithLogger(ctx, f.logger.With("request_id", requestID))
+
+		ctx = owl.WithWarnings(ctx)
 		r = r.WithContext(ctx)
 
-		rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+		rw := &responseWriter{ResponseWriter: w, status: http.StatusOK, ctx: ctx}
+
+		var spanErr error
+		if endSpan != nil {
+			defer func() {
+				trace.SpanFromContext(ctx).SetAttributes(
+					attribute.String("http.route", f.route(r)),
+					attribute.Int("http.status_code", rw.status),
+				)
+				endSpan(&spanErr)
+			}()
+		}
 
 		// 2. Panic Recovery
 		defer func() {
 			if rec := recover(); rec != nil {
+				rw.status = http.StatusInternalServerError
+				spanErr = fmt.Errorf("panic: %v", rec)
 				duration := time.Since(start).Seconds()
-				f.logger.Error(ctx, "panic recovered", nil, "panic", rec)
+				f.logger.Error(logContext(ctx), "panic recovered", nil, "panic", rec, "request_id", requestID)
 
-				// Metrics
-				reqCount.Inc(ctx, owl.Attr("status", "500"), owl.Attr("panic", "true"))
-				reqLatency.Record(ctx, duration, owl.Attr("status", "500"), owl.Attr("panic", "true"))
+				// Metrics, with the same labels as any other request
+				attrs := f.metricAttrs(ctx, r, rw.status)
+				reqCount.Inc(ctx, attrs...)
+				reqLatency.Record(ctx, duration, attrs...)
 
 				// Return 500
-				w.WriteHeader(http.StatusInternalServerError)
-				_ = json.NewEncoder(w).Encode(map[string]string{
-					"code":    "INTERNAL",
-					"message": "Internal Server Error",
-				})
+				f.writePanicResponse(w, rec)
 			}
 		}()
 
@@ -129,60 +491,170 @@ func (f *HTTPFactory) Wrap(h HTTPHandler) http.Handler {
 		err := h(rw, r)
 		duration := time.Since(start).Seconds()
 
+		// The request context may already be cancelled (client went away, hijacked
+		// connection), so log with a detached context that keeps trace correlation.
+		logCtx := logContext(ctx)
+
 		// 3. Error Handling
 		if err != nil {
+			err = mapError(f.errorMapper, err)
+			spanErr = err
 			status := owl.ToHTTPStatus(err)
 			rw.status = status // Update status for access logs if needed
 
+			// Write Response for Client using Encoder
+			// (before logging, so the logged byte count includes it)
+			f.encodeError(rw, r, err)
+
 			// Determine log level and content
 			// We log the FULL details (Msg, Err) internally
-			var obsErr *owl.Error
-			isObsErr := false
-			if asErr, ok := err.(*owl.Error); ok {
-				obsErr = asErr
-				isObsErr = true
-			}
-
-			if isObsErr {
+			// "code" is the owl code; "error_code" repeats it under the name gRPC logs use too
+			fields := f.accessLogFields(r, status, duration, rw.bytes)
+			fields = append(fields, "code", owl.CodeFromError(err).String())
+			if obsErr, ok := owl.AsError(err); ok {
 				// Log the internal message + details
-				f.logger.Error(ctx, obsErr.Msg, obsErr.Err,
-					"status", status,
-					"duration", duration,
-					"method", r.Method,
-					"path", r.URL.Path,
-				)
+				fields = append(fields, errorLogFields(obsErr)...)
+				f.logger.Error(logCtx, obsErr.Msg, obsErr.Err, fields...)
 			} else {
-				f.logger.Error(ctx, "request_failed", err,
-					"status", status,
-					"duration", duration,
-					"method", r.Method,
-					"path", r.URL.Path,
-				)
+				fields = append(fields, "error_code", owl.CodeFromError(err).String())
+				f.logger.Error(logCtx, "request_failed", err, fields...)
 			}
-
-			// Write Response for Client using Encoder
-			f.errorEncoder(w, r, err)
 		} else {
+			// Make sure warnings reach the client even if the handler wrote nothing
+			if !rw.wroteHeader {
+				rw.WriteHeader(http.StatusOK)
+			}
+
 			// 4. Success Logging
-			f.logger.Info(ctx, "request_success",
-				"status", rw.status,
-				"duration", duration,
-				"method", r.Method,
-				"path", r.URL.Path,
-			)
+			fields := f.accessLogFields(r, rw.status, duration, rw.bytes)
+			if warnings := owl.Warnings(ctx); len(warnings) > 0 {
+				fields = append(fields, "warnings", warnings)
+			}
+			f.logSuccess(logCtx, fields)
 		}
 
 		// Update Metrics
-		reqCount.Inc(ctx,
-			owl.Attr("method", r.Method),
-			owl.Attr("path", r.URL.Path),
-			// Convert status to string (Improvement: use numeric code, not StatusText)
-			owl.Attr("status", strconv.Itoa(rw.status)),
-		)
-		reqLatency.Record(ctx, duration,
-			owl.Attr("method", r.Method),
-			owl.Attr("path", r.URL.Path),
-			owl.Attr("status", strconv.Itoa(rw.status)),
-		)
+		attrs := f.metricAttrs(ctx, r, rw.status)
+		reqCount.Inc(ctx, attrs...)
+		reqLatency.Record(ctx, duration, attrs...)
+		if respSize != nil {
+			respSize.Record(ctx, float64(rw.bytes), attrs...)
+		}
 	})
 }
+
+// metricAttrs returns the labels of the request metrics. Every observation of a
+// metric must carry the same label names (Prometheus rejects the others).
+func (f *HTTPFactory) metricAttrs(ctx context.Context, r *http.Request, status int) []owl.Attribute {
+	// The route template, not the raw path, keeps label cardinality bounded
+	return append([]owl.Attribute{
+		owl.Attr("method", r.Method),
+		owl.Attr("path", f.route(r)),
+		// Convert status to string (Improvement: use numeric code, not StatusText)
+		owl.Attr("status", strconv.Itoa(status)),
+	}, baggageAttrs(ctx, f.baggageLabels)...)
+}
+
+// maxRequestIDLen bounds the length of a request ID accepted from a caller.
+const maxRequestIDLen = 128
+
+// validRequestID reports whether id, received from a caller, is safe to reuse:
+// a non-empty token of at most maxRequestIDLen letters, digits, '.', '_' or '-'.
+// Anything else (oversized values, spaces, control characters that could forge
+// log lines) is replaced by a fresh ID.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		switch c := id[i]; {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', c == '.', c == '_', c == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns a random RFC 4122 version 4 UUID.
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40 // Version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // Variant 10
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// route returns the matched ServeMux pattern, or the URL path if there is none.
+func route(r *http.Request) string {
+	if r.Pattern == "" {
+		return r.URL.Path
+	}
+	if _, path, ok := strings.Cut(r.Pattern, " "); ok {
+		// Drop the method of Go 1.22 patterns like "GET /items/{id}"
+		return strings.TrimSpace(path)
+	}
+	return r.Pattern
+}
+
+// spanName names server spans "METHOD pattern", or just the method when the
+// request was not routed by a pattern, to keep span names low-cardinality.
+func spanName(r *http.Request) string {
+	if r.Pattern == "" {
+		return r.Method
+	}
+	if strings.HasPrefix(r.Pattern, r.Method+" ") {
+		// Go 1.22 method patterns like "GET /items/{id}"
+		return r.Pattern
+	}
+	return r.Method + " " + r.Pattern
+}
+
+// baggageAttrs returns one attribute per key holding the value of that baggage
+// member in ctx, or "" if it is absent.
+func baggageAttrs(ctx context.Context, keys []string) []owl.Attribute {
+	if len(keys) == 0 {
+		return nil
+	}
+	b := baggage.FromContext(ctx)
+	attrs := make([]owl.Attribute, len(keys))
+	for i, k := range keys {
+		attrs[i] = owl.Attr(k, b.Member(k).Value())
+	}
+	return attrs
+}
+
+// logContext returns a context suitable for logging once the request is over.
+// If ctx is still live it is returned as is. Otherwise it is detached from the
+// cancellation with context.WithoutCancel, keeping the span, baggage and every
+// request-scoped value, so loggers that bail out on cancelled contexts still
+// emit the entry with its trace IDs.
+func logContext(ctx context.Context) context.Context {
+	if ctx.Err() == nil {
+		return ctx
+	}
+	return context.WithoutCancel(ctx)
+}
+
+// errorLogFields returns extra log fields describing an owl.Error: its code (as
+// "error_code", distinct from the transport status), op, Details and captured
+// stack. Details are logged as one "details" map, so a detail key cannot shadow
+// the middleware's own fields. They are internal and may hold sensitive
+// values; they go through the logger adapter's sanitizer like any other arg.
+func errorLogFields(e *owl.Error) []any {
+	fields := []any{"error_code", e.Code.String()}
+	if e.Op != "" {
+		fields = append(fields, "op", e.Op)
+	}
+	if len(e.Details) > 0 {
+		fields = append(fields, "details", e.Details)
+	}
+	if frames := e.StackTrace(); len(frames) > 0 {
+		var sb strings.Builder
+		for _, fr := range frames {
+			fmt.Fprintf(&sb, "%s\n\t%s:%d\n", fr.Function, fr.File, fr.Line)
+		}
+		fields = append(fields, "stack", sb.String())
+	}
+	return fields
+}
@@ -1,12 +1,19 @@
 package middleware
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/myuser/owl"
+	"github.com/myuser/owl/owltest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestHTTPFactory_Wrap(t *testing.T) {
@@ -77,3 +84,640 @@ func TestHTTPFactory_Wrap(t *testing.T) {
 	rw := &responseWriter{ResponseWriter: w}
 	rw.Flush() // Should not panic
 }
+
+func TestHTTPFactory_PanicMetricLabels(t *testing.T) {
+	monitor := owltest.NewMonitor()
+	f := NewHTTPFactory(nil, monitor)
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /jobs/{id}", f.Wrap(func(w http.ResponseWriter, r *http.Request) error {
+		panic("oops")
+	}))
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/jobs/7", nil))
+
+	// Same label names as other requests, so label-strict backends keep it
+	attrs := []owl.Attribute{owl.Attr("method", "GET"), owl.Attr("path", "/jobs/{id}"), owl.Attr("status", "500")}
+	if got := monitor.GetCounterWith("http_requests_total", attrs...); got != 1 {
+		t.Errorf("Expected the panic counted under the regular labels, got %v", got)
+	}
+}
+
+// ctxLogger records the context passed to the last log call.
+type ctxLogger struct {
+	owl.NoOpLogger
+	last context.Context
+}
+
+func (l *ctxLogger) Info(ctx context.Context, msg string, args ...any) {
+	l.last = ctx
+}
+
+func TestHTTPFactory_Wrap_CancelledContext(t *testing.T) {
+	logger := &ctxLogger{}
+	f := NewHTTPFactory(logger, nil)
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx, cancel := context.WithCancel(trace.ContextWithSpanContext(context.Background(), sc))
+
+	h := f.Wrap(func(w http.ResponseWriter, r *http.Request) error {
+		cancel() // Simulate the client going away mid-request
+		return nil
+	})
+
+	req := httptest.NewRequest("GET", "/stream", nil).WithContext(ctx)
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	if logger.last == nil {
+		t.Fatal("Expected success log")
+	}
+	if logger.last.Err() != nil {
+		t.Errorf("Expected live log context, got %v", logger.last.Err())
+	}
+	if got := trace.SpanContextFromContext(logger.last).TraceID(); got != sc.TraceID() {
+		t.Errorf("Expected trace ID %s, got %s", sc.TraceID(), got)
+	}
+	if owl.RequestIDFromContext(logger.last) == "" {
+		t.Error("Expected request-scoped values to survive in the log context")
+	}
+}
+
+func TestHTTPFactory_WrapNamed(t *testing.T) {
+	f := NewHTTPFactory(nil, nil)
+
+	var op string
+	h := f.WrapNamed("Item.Get", func(w http.ResponseWriter, r *http.Request) error {
+		op = owl.Problem(owl.NotFound, owl.WithContext(r.Context())).Op
+		return nil
+	})
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/items/1", nil))
+
+	if op != "Item.Get" {
+		t.Errorf("Expected Op 'Item.Get', got %q", op)
+	}
+}
+
+func TestHTTPFactory_Warnings(t *testing.T) {
+	f := NewHTTPFactory(nil, nil)
+
+	h := f.Wrap(func(w http.ResponseWriter, r *http.Request) error {
+		owl.AddWarning(r.Context(), "DEPRECATED", "use /v2/items")
+		w.Write([]byte("ok"))
+		return nil
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/v1/items", nil))
+
+	want := `299 - "DEPRECATED: use /v2/items"`
+	if got := w.Header().Get("Warning"); got != want {
+		t.Errorf("Expected Warning header %q, got %q", want, got)
+	}
+	if w.Body.String() != "ok" {
+		t.Errorf("Unexpected body %q", w.Body.String())
+	}
+}
+
+func TestHTTPFactory_LogsStack(t *testing.T) {
+	logger := owltest.NewLogger()
+	f := NewHTTPFactory(logger, nil)
+
+	h := f.Wrap(func(w http.ResponseWriter, r *http.Request) error {
+		return owl.Problem(owl.Internal, owl.WithMsg("boom"), owl.WithStack())
+	})
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	entry := logger.Find("boom")
+	if entry == nil {
+		t.Fatal("Expected error log")
+	}
+	logged, _ := entry.Field("stack")
+	stack, _ := logged.(string)
+	if !strings.Contains(stack, "TestHTTPFactory_LogsStack") {
+		t.Errorf("Expected stack to contain the handler frame, got %q", stack)
+	}
+}
+
+func TestHTTPFactory_InFlight(t *testing.T) {
+	monitor := owltest.NewMonitor()
+	f := NewHTTPFactory(nil, monitor)
+
+	var during float64
+	h := f.Wrap(func(w http.ResponseWriter, r *http.Request) error {
+		during = monitor.GetGauge("http_requests_in_flight")
+		return nil
+	})
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	if n := monitor.HistogramCount("http_request_duration_seconds"); n != 1 {
+		t.Errorf("Expected 1 latency sample, got %d", n)
+	}
+	if during != 1 {
+		t.Errorf("Expected 1 in-flight request during handler, got %v", during)
+	}
+	if after := monitor.GetGauge("http_requests_in_flight"); after != 0 {
+		t.Errorf("Expected 0 in-flight requests after handler, got %v", after)
+	}
+}
+
+func TestHTTPFactory_RequestID(t *testing.T) {
+	logger := owltest.NewLogger()
+	f := NewHTTPFactory(logger, nil)
+
+	var seen string
+	h := f.Wrap(func(w http.ResponseWriter, r *http.Request) error {
+		seen = owl.RequestIDFromContext(r.Context())
+		return nil
+	})
+
+	// Incoming ID is reused and echoed
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Request-ID", "req-123")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if seen != "req-123" {
+		t.Errorf("Expected request ID in context, got %q", seen)
+	}
+	if got := w.Header().Get("X-Request-ID"); got != "req-123" {
+		t.Errorf("Expected request ID header to be echoed, got %q", got)
+	}
+	entry := logger.Find("request_success")
+	if entry == nil {
+		t.Fatal("Expected success log")
+	}
+	if logged, _ := entry.Field("request_id"); logged != "req-123" {
+		t.Errorf("Expected request_id log field, got %v", logged)
+	}
+
+	// Missing ID is generated, using a custom header
+	f = NewHTTPFactory(nil, nil, WithRequestIDHeader("X-Correlation-ID"))
+	h = f.Wrap(func(w http.ResponseWriter, r *http.Request) error {
+		seen = owl.RequestIDFromContext(r.Context())
+		return owl.Problem(owl.NotFound)
+	})
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if len(seen) != 36 {
+		t.Errorf("Expected generated UUID, got %q", seen)
+	}
+	if got := w.Header().Get("X-Correlation-ID"); got != seen {
+		t.Errorf("Expected generated ID to be echoed, got %q", got)
+	}
+
+	// Unsafe incoming IDs are replaced
+	for _, id := range []string{"req-1\nlevel=ERROR msg=forged", "req 1", strings.Repeat("a", 129)} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("X-Correlation-ID", id)
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		if len(seen) != 36 {
+			t.Errorf("Expected %q to be replaced by a generated UUID, got %q", id, seen)
+		}
+	}
+}
+
+func TestHTTPFactory_ContextLogger(t *testing.T) {
+	logger := owltest.NewLogger()
+	f := NewHTTPFactory(logger, nil)
+
+	h := f.Wrap(func(w http.ResponseWriter, r *http.Request) error {
+		owl.LoggerFromContext(r.Context()).Info(r.Context(), "loading profile")
+		return nil
+	})
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Request-ID", "req-123")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	entry := logger.Find("loading profile")
+	if entry == nil {
+		t.Fatal("Expected the handler to log through the request logger")
+	}
+	if id, _ := entry.Field("request_id"); id != "req-123" {
+		t.Errorf("Expected bound request_id, got %v", entry.Args)
+	}
+}
+
+func TestHTTPFactory_RouteLabel(t *testing.T) {
+	ok := func(w http.ResponseWriter, r *http.Request) error { return nil }
+	attrs := func(path string) []owl.Attribute {
+		return []owl.Attribute{owl.Attr("method", "GET"), owl.Attr("path", path), owl.Attr("status", "200")}
+	}
+
+	// ServeMux pattern is picked up automatically
+	monitor := owltest.NewMonitor()
+	mux := http.NewServeMux()
+	mux.Handle("GET /users/{id}", NewHTTPFactory(nil, monitor).Wrap(ok))
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/123", nil))
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/456", nil))
+
+	if got := monitor.GetCounterWith("http_requests_total", attrs("/users/{id}")...); got != 2 {
+		t.Errorf("Expected 2 requests for the pattern, got %v", got)
+	}
+
+	// Custom resolver wins
+	monitor = owltest.NewMonitor()
+	f := NewHTTPFactory(nil, monitor, WithRouteResolver(func(r *http.Request) string {
+		return "/orders/:id"
+	}))
+	f.Wrap(ok).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/orders/9", nil))
+
+	if got := monitor.GetCounterWith("http_requests_total", attrs("/orders/:id")...); got != 1 {
+		t.Errorf("Expected resolved template as label, got %v", got)
+	}
+	if got := monitor.GetCounterWith("http_requests_total", attrs("/orders/9")...); got != 0 {
+		t.Errorf("Expected raw path not to be used, got %v", got)
+	}
+}
+
+func TestHTTPFactory_SkipPaths(t *testing.T) {
+	logger := owltest.NewLogger()
+	monitor := owltest.NewMonitor()
+	f := NewHTTPFactory(logger, monitor, WithSkipPaths("/healthz"))
+
+	called := false
+	h := f.Wrap(func(w http.ResponseWriter, r *http.Request) error {
+		called = true
+		if r.URL.Query().Get("panic") != "" {
+			panic("boom")
+		}
+		return nil
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/healthz", nil))
+
+	if !called {
+		t.Error("Expected skipped handler to run")
+	}
+	if len(logger.Entries) != 0 {
+		t.Errorf("Expected no logs for skipped path, got %v", logger.Entries)
+	}
+	if got := monitor.GetCounter("http_requests_total"); got != 0 {
+		t.Errorf("Expected no request metric for skipped path, got %v", got)
+	}
+
+	// Panics are still recovered
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/healthz?panic=1", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected 500 after panic, got %d", w.Code)
+	}
+
+	// Other paths are instrumented
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users", nil))
+	if got := monitor.GetCounter("http_requests_total"); got != 1 {
+		t.Errorf("Expected 1 request metric, got %v", got)
+	}
+}
+
+func TestHTTPFactory_ResponseBytes(t *testing.T) {
+	logger := owltest.NewLogger()
+	monitor := owltest.NewMonitor()
+	f := NewHTTPFactory(logger, monitor, WithResponseSizeMetric(true))
+
+	payload := "hello, owl"
+	h := f.Wrap(func(w http.ResponseWriter, r *http.Request) error {
+		w.Write([]byte(payload[:5]))
+		w.(http.Flusher).Flush()
+		w.Write([]byte(payload[5:]))
+		return nil
+	})
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	entry := logger.Find("request_success")
+	if entry == nil {
+		t.Fatal("Expected success log")
+	}
+	if logged, _ := entry.Field("bytes"); logged != int64(len(payload)) {
+		t.Errorf("Expected %d bytes logged, got %v", len(payload), logged)
+	}
+	if sum := monitor.HistogramSum("http_response_size_bytes"); sum != float64(len(payload)) {
+		t.Errorf("Expected response size %d recorded, got %v", len(payload), sum)
+	}
+
+	// Error responses written by the encoder are counted too
+	h = f.Wrap(func(w http.ResponseWriter, r *http.Request) error {
+		return owl.Problem(owl.NotFound, owl.WithMsg("lookup failed"))
+	})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	entry = logger.Find("lookup failed")
+	if entry == nil {
+		t.Fatal("Expected error log")
+	}
+	if logged, _ := entry.Field("bytes"); logged != int64(w.Body.Len()) {
+		t.Errorf("Expected %d error bytes logged, got %v", w.Body.Len(), logged)
+	}
+}
+
+func TestHTTPFactory_WrappedOwlError(t *testing.T) {
+	logger := owltest.NewLogger()
+	f := NewHTTPFactory(logger, nil)
+
+	h := f.Wrap(func(w http.ResponseWriter, r *http.Request) error {
+		err := owl.Problem(owl.NotFound, owl.WithMsg("user 42 missing"), owl.WithSafeMsg("user not found"))
+		return fmt.Errorf("loading profile: %w", err)
+	})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "user not found") {
+		t.Errorf("Expected safe message in body, got %s", w.Body.String())
+	}
+	if logger.Find("user 42 missing") == nil {
+		t.Error("Expected the wrapped owl error's message to be logged")
+	}
+}
+
+func TestHTTPFactory_InternalDetailsNotInBody(t *testing.T) {
+	logger := owltest.NewLogger()
+	f := NewHTTPFactory(logger, nil)
+
+	h := f.Wrap(func(w http.ResponseWriter, r *http.Request) error {
+		return owl.Problem(owl.Invalid,
+			owl.WithMsg("signup rejected"),
+			owl.WithSafeDetails(map[string]any{"field": "email"}),
+			owl.WithDetails(map[string]any{"password_hash": "secret-hash"}),
+		)
+	})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/signup", nil))
+
+	if strings.Contains(w.Body.String(), "secret-hash") || strings.Contains(w.Body.String(), "password_hash") {
+		t.Errorf("Internal details leaked into the response: %s", w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"details":{"field":"email"}`) {
+		t.Errorf("Expected safe details in the response, got %s", w.Body.String())
+	}
+	entry := logger.Find("signup rejected")
+	if v, _ := entry.Field("details"); v.(map[string]any)["password_hash"] != "secret-hash" {
+		t.Errorf("Expected internal details in the log, got %v", entry.Args)
+	}
+}
+
+func TestHTTPFactory_LogsDetails(t *testing.T) {
+	logger := owltest.NewLogger()
+	f := NewHTTPFactory(logger, nil)
+
+	h := f.Wrap(func(w http.ResponseWriter, r *http.Request) error {
+		return owl.Problem(owl.NotFound,
+			owl.WithMsg("lookup failed"),
+			owl.WithOp("User.Get"),
+			owl.WithField("user_id", "42"),
+			owl.WithField("status", "suspended"),
+		)
+	})
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	entry := logger.Find("lookup failed")
+	if entry == nil {
+		t.Fatal("Expected error log")
+	}
+	details, _ := entry.Field("details")
+	if d, _ := details.(map[string]any); d["user_id"] != "42" || d["status"] != "suspended" {
+		t.Errorf("Expected details grouped in log args, got %v", entry.Args)
+	}
+	if v, _ := entry.Field("status"); v != http.StatusNotFound {
+		t.Errorf("Expected a detail not to shadow the status field, got %v", v)
+	}
+	op, _ := entry.Field("op")
+	code, _ := entry.Field("code")
+	errorCode, _ := entry.Field("error_code")
+	if op != "User.Get" || code != "NOT_FOUND" || errorCode != "NOT_FOUND" {
+		t.Errorf("Expected op, code and error_code in log args, got %v", entry.Args)
+	}
+
+	h = NewHTTPFactory(logger, nil).Wrap(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("boom")
+	})
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	failed := logger.Find("request_failed")
+	code, _ = failed.Field("code")
+	errorCode, _ = failed.Field("error_code")
+	if code != "INTERNAL" || errorCode != "INTERNAL" {
+		t.Errorf("Expected code and error_code INTERNAL for a plain error, got %v", failed.Args)
+	}
+}
+
+func TestHTTPFactory_DebugErrors(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		if r.URL.Path == "/panic" {
+			panic("nil map write")
+		}
+		return errors.New("dial tcp 10.0.0.5:5432: connection refused")
+	}
+
+	decode := func(t *testing.T, w *httptest.ResponseRecorder) map[string]any {
+		t.Helper()
+		var body map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Invalid JSON body %q: %v", w.Body.String(), err)
+		}
+		return body
+	}
+
+	t.Run("production", func(t *testing.T) {
+		h := NewHTTPFactory(nil, nil).Wrap(handler)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/panic", nil))
+		body := decode(t, w)
+		if body["message"] != "Internal Server Error" || body["panic"] != nil || body["stack"] != nil {
+			t.Errorf("Expected generic panic body, got %v", body)
+		}
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/error", nil))
+		if body := decode(t, w); body["message"] != "Internal Server Error" {
+			t.Errorf("Expected obscured error message, got %v", body)
+		}
+	})
+
+	t.Run("debug", func(t *testing.T) {
+		h := NewHTTPFactory(nil, nil, WithDebugErrors(true)).Wrap(handler)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/panic", nil))
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("Expected 500, got %d", w.Code)
+		}
+		body := decode(t, w)
+		if body["panic"] != "nil map write" {
+			t.Errorf("Expected panic value in body, got %v", body)
+		}
+		if stack, _ := body["stack"].(string); !strings.Contains(stack, "TestHTTPFactory_DebugErrors") {
+			t.Errorf("Expected stack pointing at the handler, got %q", body["stack"])
+		}
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/error", nil))
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("Expected 500, got %d", w.Code)
+		}
+		if body := decode(t, w); body["message"] != "dial tcp 10.0.0.5:5432: connection refused" {
+			t.Errorf("Expected real error message, got %v", body)
+		}
+	})
+}
+
+func TestHTTPFactory_ErrorMapper(t *testing.T) {
+	errNoRows := errors.New("no rows in result set")
+	logger := owltest.NewLogger()
+	f := NewHTTPFactory(logger, nil, WithErrorMapper(func(err error) *owl.Error {
+		if errors.Is(err, errNoRows) {
+			return owl.Problem(owl.NotFound, owl.WithMsg("user lookup"), owl.WithErr(err))
+		}
+		return nil
+	}))
+
+	h := f.Wrap(func(w http.ResponseWriter, r *http.Request) error {
+		if r.URL.Path == "/missing" {
+			return fmt.Errorf("get user: %w", errNoRows)
+		}
+		return errors.New("boom")
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/missing", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected mapped 404, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `"code":"NOT_FOUND"`) {
+		t.Errorf("Expected NOT_FOUND body, got %s", w.Body.String())
+	}
+	if entry := logger.Find("user lookup"); entry == nil || !errors.Is(entry.Error, errNoRows) {
+		t.Error("Expected the mapped error to be logged with its cause")
+	}
+
+	// Unmapped errors flow through unchanged
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/other", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected 500 for unmapped error, got %d", w.Code)
+	}
+}
+
+func TestHTTPFactory_ContextErrors(t *testing.T) {
+	h := NewHTTPFactory(nil, nil).Wrap(func(w http.ResponseWriter, r *http.Request) error {
+		return fmt.Errorf("query users: %w", context.DeadlineExceeded)
+	})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/users", nil))
+
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected 504, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `"code":"DEADLINE_EXCEEDED"`) {
+		t.Errorf("Expected body to match the status, got %s", w.Body.String())
+	}
+}
+
+func TestHTTPFactory_BaggageMetricLabels(t *testing.T) {
+	monitor := owltest.NewMonitor()
+	f := NewHTTPFactory(nil, monitor, WithBaggageMetricLabels("tenant_id", "plan"))
+	h := f.Wrap(func(w http.ResponseWriter, r *http.Request) error { return nil })
+
+	req := httptest.NewRequest("GET", "/items", nil)
+	req = req.WithContext(owl.SetBaggage(req.Context(), "tenant_id", "acme"))
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	got := monitor.GetCounterWith("http_requests_total",
+		owl.Attr("method", "GET"),
+		owl.Attr("path", "/items"),
+		owl.Attr("status", "200"),
+		owl.Attr("tenant_id", "acme"),
+		owl.Attr("plan", ""),
+	)
+	if got != 1 {
+		t.Errorf("Expected 1 request labelled with tenant_id and an empty plan, got %v", got)
+	}
+}
+
+func TestHTTPFactory_AccessLogFields(t *testing.T) {
+	logger := owltest.NewLogger()
+	f := NewHTTPFactory(logger, nil, WithAccessLogFields(func(r *http.Request, status int, duration float64) []any {
+		return []any{
+			"http.method", r.Method,
+			"url.path", r.URL.Path,
+			"http.status_code", status,
+			"request.id", owl.RequestIDFromContext(r.Context()),
+		}
+	}))
+	h := f.Wrap(func(w http.ResponseWriter, r *http.Request) error {
+		if r.URL.Path == "/fail" {
+			return owl.Problem(owl.NotFound, owl.WithMsg("item missing"))
+		}
+		return nil
+	})
+
+	req := httptest.NewRequest("GET", "/items", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	entry := logger.Find("request_success")
+	if entry == nil {
+		t.Fatal("Expected access log")
+	}
+	for key, want := range map[string]any{"http.method": "GET", "url.path": "/items", "http.status_code": 200, "request.id": "req-1"} {
+		if got, _ := entry.Field(key); got != want {
+			t.Errorf("%s = %v, want %v", key, got, want)
+		}
+	}
+	if _, ok := entry.Field("method"); ok {
+		t.Errorf("Expected default fields to be replaced, got %v", entry.Args)
+	}
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/fail", nil))
+	entry = logger.Find("item missing")
+	if got, _ := entry.Field("http.status_code"); got != http.StatusNotFound {
+		t.Errorf("Expected custom fields on error logs, got %v", entry.Args)
+	}
+	if code, _ := entry.Field("error_code"); code != "NOT_FOUND" {
+		t.Errorf("Expected error fields to still be appended, got %v", entry.Args)
+	}
+}
+
+func TestHTTPFactory_SuccessLogLevel(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		if r.URL.Path == "/fail" {
+			return errors.New("boom")
+		}
+		return nil
+	}
+	tests := []struct {
+		name  string
+		opts  []func(*HTTPFactory)
+		level string // "" when no success log is expected
+	}{
+		{"default", nil, "INFO"},
+		{"debug", []func(*HTTPFactory){WithSuccessLogLevel(slog.LevelDebug)}, "DEBUG"},
+		{"disabled", []func(*HTTPFactory){WithAccessLogEnabled(false)}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := owltest.NewLogger()
+			h := NewHTTPFactory(logger, nil, tt.opts...).Wrap(handler)
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/ok", nil))
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/fail", nil))
+
+			entry := logger.Find("request_success")
+			switch {
+			case tt.level == "" && entry != nil:
+				t.Errorf("Expected no success log, got %+v", entry)
+			case tt.level != "" && (entry == nil || entry.Level != tt.level):
+				t.Errorf("Expected success log at %s, got %+v", tt.level, entry)
+			}
+			if !logger.HasEntry("ERROR", "request_failed") {
+				t.Error("Expected failures to be logged at Error regardless")
+			}
+		})
+	}
+}
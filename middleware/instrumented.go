@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/myuser/owl"
+)
+
+// ClientOption configures an instrumented Client.
+type ClientOption func(*clientConfig)
+
+type clientConfig struct {
+	base     http.RoundTripper
+	logger   owl.Logger
+	timeout  time.Duration
+	wrappers []func(http.RoundTripper) http.RoundTripper
+}
+
+// WithClientTransport sets the underlying transport. Defaults to http.DefaultTransport.
+func WithClientTransport(rt http.RoundTripper) ClientOption {
+	return func(c *clientConfig) {
+		c.base = rt
+	}
+}
+
+// WithClientLogger sets the logger used for outbound request logs.
+func WithClientLogger(l owl.Logger) ClientOption {
+	return func(c *clientConfig) {
+		c.logger = l
+	}
+}
+
+// WithClientTimeout sets the overall timeout of the returned http.Client.
+func WithClientTimeout(d time.Duration) ClientOption {
+	return func(c *clientConfig) {
+		c.timeout = d
+	}
+}
+
+// WithTransportMiddleware wraps the instrumented transport.
+// Wrappers are applied in order, so the last one is the outermost.
+func WithTransportMiddleware(wrap func(http.RoundTripper) http.RoundTripper) ClientOption {
+	return func(c *clientConfig) {
+		c.wrappers = append(c.wrappers, wrap)
+	}
+}
+
+// Client is an *http.Client with owl instrumentation and JSON helpers.
+type Client struct {
+	*http.Client
+}
+
+// InstrumentedClient builds a Client whose transport injects trace context and
+// logs every outbound request. Extra behaviour (retries, circuit breaking) is
+// layered on top via ClientOptions.
+func InstrumentedClient(opts ...ClientOption) *Client {
+	cfg := &clientConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	var rt http.RoundTripper = NewHTTPClient(cfg.base, cfg.logger)
+	for _, wrap := range cfg.wrappers {
+		rt = wrap(rt)
+	}
+
+	return &Client{
+		Client: &http.Client{
+			Transport: rt,
+			Timeout:   cfg.timeout,
+		},
+	}
+}
+
+// GetJSON issues a GET request and decodes a successful JSON response into out.
+// Any failure is returned as an *owl.Error; upstream errors are hydrated via CheckResponse.
+func (c *Client) GetJSON(ctx context.Context, url string, out any) error {
+	return c.doJSON(ctx, http.MethodGet, url, nil, out)
+}
+
+// PostJSON encodes in as the JSON request body, issues a POST request and
+// decodes a successful JSON response into out.
+func (c *Client) PostJSON(ctx context.Context, url string, in, out any) error {
+	return c.doJSON(ctx, http.MethodPost, url, in, out)
+}
+
+func (c *Client) doJSON(ctx context.Context, method, url string, in, out any) error {
+	op := "Client." + method
+
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return owl.Problem(owl.Invalid, owl.WithOp(op), owl.WithMsg("failed to encode request body"), owl.WithErr(err))
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return owl.Problem(owl.Invalid, owl.WithOp(op), owl.WithMsg("failed to build request"), owl.WithErr(err))
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		// Our own deadline or cancellation is not a downstream outage
+		code := owl.CodeFromError(err)
+		if code != owl.DeadlineExceeded && code != owl.Canceled {
+			code = owl.Unavailable
+		}
+		return owl.Problem(code, owl.WithOp(op), owl.WithMsg("request failed"), owl.WithErr(err))
+	}
+
+	defer resp.Body.Close()
+	if err := CheckResponse(resp); err != nil {
+		return err
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && err != io.EOF {
+		return owl.Problem(owl.Internal, owl.WithOp(op), owl.WithMsg("failed to decode response body"), owl.WithErr(err))
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/myuser/owl"
+)
+
+func TestInstrumentedClient_JSON(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":"NOT_FOUND","message":"no such item"}`))
+		case "/echo":
+			var in map[string]string
+			json.NewDecoder(r.Body).Decode(&in)
+			json.NewEncoder(w).Encode(in)
+		default:
+			json.NewEncoder(w).Encode(map[string]string{"name": "owl"})
+		}
+	}))
+	defer ts.Close()
+
+	wrapped := false
+	client := InstrumentedClient(
+		WithClientLogger(owl.NoOpLogger{}),
+		WithTransportMiddleware(func(next http.RoundTripper) http.RoundTripper {
+			wrapped = true
+			return next
+		}),
+	)
+	if !wrapped {
+		t.Error("Transport middleware not applied")
+	}
+	ctx := context.Background()
+
+	var out map[string]string
+	if err := client.GetJSON(ctx, ts.URL+"/item", &out); err != nil {
+		t.Fatalf("GetJSON failed: %v", err)
+	}
+	if out["name"] != "owl" {
+		t.Errorf("Expected name 'owl', got %v", out["name"])
+	}
+
+	out = nil
+	if err := client.PostJSON(ctx, ts.URL+"/echo", map[string]string{"k": "v"}, &out); err != nil {
+		t.Fatalf("PostJSON failed: %v", err)
+	}
+	if out["k"] != "v" {
+		t.Errorf("Expected echoed body, got %v", out)
+	}
+
+	err := client.GetJSON(ctx, ts.URL+"/missing", &out)
+	if !errors.Is(err, owl.NotFound) {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+}
+
+func TestInstrumentedClient_TransportError(t *testing.T) {
+	client := InstrumentedClient(WithClientTransport(&mockTransport{
+		RoundTripFunc: func(r *http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		},
+	}))
+
+	err := client.GetJSON(context.Background(), "http://example.com", nil)
+	if !errors.Is(err, owl.Unavailable) {
+		t.Errorf("Expected Unavailable, got %v", err)
+	}
+}
+
+func TestInstrumentedClient_Canceled(t *testing.T) {
+	client := InstrumentedClient(WithClientTransport(&mockTransport{
+		RoundTripFunc: func(r *http.Request) (*http.Response, error) {
+			return nil, r.Context().Err()
+		},
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := client.GetJSON(ctx, "http://example.com", nil)
+	if !errors.Is(err, owl.Canceled) {
+		t.Errorf("Expected Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/myuser/owl"
+)
+
+// RetryTransport retries idempotent requests that fail with a transient error:
+// a transport error, a 5xx, or a response hydrated as owl.Unavailable or
+// owl.DeadlineExceeded. Delays grow exponentially with jitter, and a
+// Retry-After header on the response takes precedence.
+type RetryTransport struct {
+	Base        http.RoundTripper
+	Logger      owl.Logger
+	MaxAttempts int           // Including the first attempt
+	BaseDelay   time.Duration // Delay before the first retry, doubled each time
+	MaxDelay    time.Duration // Upper bound for the computed backoff
+}
+
+// NewRetryTransport wraps base with retries. Defaults: 3 attempts, 100ms base delay, 2s max delay.
+func NewRetryTransport(base http.RoundTripper, logger owl.Logger, opts ...func(*RetryTransport)) *RetryTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if logger == nil {
+		logger = owl.NoOpLogger{}
+	}
+	t := &RetryTransport{
+		Base:        base,
+		Logger:      logger,
+		MaxAttempts: 3,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    2 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// WithRetryMaxAttempts caps the number of attempts, including the first one.
+func WithRetryMaxAttempts(n int) func(*RetryTransport) {
+	return func(t *RetryTransport) {
+		t.MaxAttempts = n
+	}
+}
+
+// WithRetryBackoff sets the initial and maximum backoff delays.
+func WithRetryBackoff(base, max time.Duration) func(*RetryTransport) {
+	return func(t *RetryTransport) {
+		t.BaseDelay = base
+		t.MaxDelay = max
+	}
+}
+
+// WithRetry adds a RetryTransport to an InstrumentedClient, logging through the client's logger.
+// Each attempt goes through the instrumented transport, so it gets its own span and log line.
+func WithRetry(opts ...func(*RetryTransport)) ClientOption {
+	return func(c *clientConfig) {
+		c.wrappers = append(c.wrappers, func(next http.RoundTripper) http.RoundTripper {
+			return NewRetryTransport(next, c.logger, opts...)
+		})
+	}
+}
+
+// RoundTrip executes the request, retrying transient failures.
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	canRetry := isIdempotent(req) && (req.Body == nil || req.Body == http.NoBody || req.GetBody != nil)
+
+	for attempt := 1; ; attempt++ {
+		resp, err := t.Base.RoundTrip(req)
+		if !canRetry || attempt >= t.MaxAttempts || !shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt)
+		if resp != nil {
+			if d, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+				delay = d
+			}
+		}
+		// Give up early rather than sleep past the caller's deadline
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return resp, err
+		}
+
+		fields := []any{
+			"attempt", attempt,
+			"delay", delay.Seconds(),
+			"method", req.Method,
+			"url", redactURL(req.URL),
+		}
+		if resp != nil {
+			fields = append(fields, "status", resp.StatusCode)
+			drain(resp.Body)
+		}
+		if err != nil {
+			fields = append(fields, "error", err.Error())
+		}
+		t.Logger.Warn(ctx, "outbound_request_retry", fields...)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+	}
+}
+
+// backoff returns the jittered exponential delay before retry number attempt.
+func (t *RetryTransport) backoff(attempt int) time.Duration {
+	d := t.BaseDelay << (attempt - 1)
+	if d > t.MaxDelay || d <= 0 {
+		d = t.MaxDelay
+	}
+	// Jitter in [d/2, d] spreads out retries from many clients
+	half := int64(d / 2)
+	return time.Duration(half + rand.Int64N(half+1))
+}
+
+// shouldRetry classifies the outcome of a single attempt.
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		// A cancelled request is not transient
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	if resp.StatusCode >= 500 {
+		return true
+	}
+	hydrated := CheckResponse(resp)
+	return errors.Is(hydrated, owl.Unavailable) || errors.Is(hydrated, owl.DeadlineExceeded)
+}
+
+// isIdempotent reports whether req can be safely sent more than once.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
+
+// drain discards a bounded amount of body so the connection can be reused, then closes it.
+func drain(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, 64*1024))
+	_ = body.Close()
+}
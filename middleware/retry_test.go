@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/myuser/owl/owltest"
+)
+
+func TestRetryTransport(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	logger := owltest.NewLogger()
+	client := &http.Client{Transport: NewRetryTransport(nil, logger, WithRetryBackoff(time.Millisecond, 5*time.Millisecond))}
+
+	resp, err := client.Get(ts.URL + "/?token=secret")
+	if err != nil {
+		t.Fatalf("Expected success after retries, got %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200, got %d", resp.StatusCode)
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("Expected 3 attempts, got %d", n)
+	}
+	if n := logger.CountLevel("WARN"); n != 2 {
+		t.Errorf("Expected 2 retry logs, got %d", n)
+	}
+	if v, _ := logger.Find("outbound_request_retry").Field("url"); v != ts.URL+"/" {
+		t.Errorf("Expected the url logged without the query, got %v", v)
+	}
+}
+
+func TestRetryTransport_NoRetry(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	client := &http.Client{Transport: NewRetryTransport(nil, nil, WithRetryBackoff(time.Millisecond, time.Millisecond))}
+
+	// Non-idempotent requests are sent once
+	resp, err := client.Post(ts.URL, "text/plain", strings.NewReader("x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if n := calls.Swap(0); n != 1 {
+		t.Errorf("Expected POST to be sent once, got %d", n)
+	}
+
+	// Attempts are capped
+	resp, err = client.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if n := calls.Swap(0); n != 3 {
+		t.Errorf("Expected 3 attempts, got %d", n)
+	}
+}
+
+func TestRetryTransport_RetryAfterBeyondDeadline(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "10")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	client := &http.Client{Transport: NewRetryTransport(nil, nil)}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || calls.Load() != 1 {
+		t.Errorf("Expected to give up without waiting, got status %d after %d calls", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	if d, ok := retryAfter("2"); !ok || d != 2*time.Second {
+		t.Errorf("Expected 2s, got %v %v", d, ok)
+	}
+	if d, ok := retryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)); !ok || d < 59*time.Minute {
+		t.Errorf("Expected about 1h, got %v %v", d, ok)
+	}
+	if _, ok := retryAfter("soon"); ok {
+		t.Error("Expected invalid value to be ignored")
+	}
+}
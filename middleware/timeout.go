@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/myuser/owl"
+)
+
+// Timeout returns a middleware that bounds each request to d.
+// The handler runs with a context that expires after d; if it returns once the
+// deadline has passed, the request fails with owl.DeadlineExceeded (504),
+// unless the handler already wrote a response and returned no context error.
+//
+// The handler runs on the request goroutine, so nothing is leaked, but it is
+// the handler's responsibility to honor ctx and stop work early. A handler that
+// ignores ctx still holds the request until it returns.
+func (f *HTTPFactory) Timeout(d time.Duration) HTTPMiddleware {
+	return func(next HTTPHandler) HTTPHandler {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			tw := &timeoutWriter{ResponseWriter: w}
+			err := next(tw, r.WithContext(ctx))
+			if !errors.Is(ctx.Err(), context.DeadlineExceeded) || r.Context().Err() != nil {
+				return err
+			}
+			// A response already sent stands, unless the handler gave up on ctx
+			if tw.wrote && !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled) {
+				return err
+			}
+			return owl.Problem(owl.DeadlineExceeded,
+				owl.WithMsg("request exceeded timeout of "+d.String()),
+				owl.WithSafeMsg("request timed out"),
+				owl.WithErr(err),
+			)
+		}
+	}
+}
+
+// timeoutWriter records whether the handler started the response.
+type timeoutWriter struct {
+	http.ResponseWriter
+	wrote bool
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.wrote = true
+	tw.ResponseWriter.WriteHeader(code)
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.wrote = true
+	return tw.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher for streaming handlers.
+func (tw *timeoutWriter) Flush() {
+	if f, ok := tw.ResponseWriter.(http.Flusher); ok {
+		tw.wrote = true
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (tw *timeoutWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+}
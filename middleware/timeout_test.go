@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/myuser/owl"
+	"github.com/myuser/owl/owltest"
+)
+
+func TestHTTPFactory_Timeout(t *testing.T) {
+	f := NewHTTPFactory(nil, nil)
+	timeout := f.Timeout(10 * time.Millisecond)
+
+	slow := f.Wrap(timeout(func(w http.ResponseWriter, r *http.Request) error {
+		select {
+		case <-time.After(time.Second):
+			return nil
+		case <-r.Context().Done():
+			return r.Context().Err()
+		}
+	}))
+	w := httptest.NewRecorder()
+	slow.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected 504, got %d", w.Code)
+	}
+	if body := w.Body.String(); !strings.Contains(body, "request timed out") {
+		t.Errorf("Expected safe message in body, got %s", body)
+	}
+
+	fast := f.Wrap(timeout(func(w http.ResponseWriter, r *http.Request) error {
+		if _, ok := r.Context().Deadline(); !ok {
+			t.Error("Expected request context to carry a deadline")
+		}
+		return owl.Problem(owl.NotFound)
+	}))
+	w = httptest.NewRecorder()
+	fast.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected handler error to pass through, got %d", w.Code)
+	}
+}
+
+func TestHTTPFactory_TimeoutAfterResponse(t *testing.T) {
+	logger := owltest.NewLogger()
+	f := NewHTTPFactory(logger, nil)
+
+	// The handler ignores ctx, answers, and returns after the deadline
+	h := f.Wrap(f.Timeout(time.Millisecond)(func(w http.ResponseWriter, r *http.Request) error {
+		w.Write([]byte("done"))
+		<-r.Context().Done()
+		return nil
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if w.Code != http.StatusOK || w.Body.String() != "done" {
+		t.Errorf("Expected the handler's response untouched, got %d %q", w.Code, w.Body.String())
+	}
+	if n := logger.CountLevel("ERROR"); n != 0 {
+		t.Errorf("Expected no error logged, got %v", logger.Entries)
+	}
+}
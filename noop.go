@@ -9,6 +9,7 @@ func (NoOpLogger) Debug(ctx context.Context, msg string, args ...any)
 func (NoOpLogger) Info(ctx context.Context, msg string, args ...any)             {}
 func (NoOpLogger) Warn(ctx context.Context, msg string, args ...any)             {}
 func (NoOpLogger) Error(ctx context.Context, msg string, err error, args ...any) {}
+func (NoOpLogger) With(args ...any) Logger                                       { return NoOpLogger{} }
 
 // NoOpMonitor is a monitor that does nothing.
 type NoOpMonitor struct{}
@@ -19,6 +20,14 @@ func (NoOpMonitor) Counter(name string, opts ...MetricOption) Counter {
 func (NoOpMonitor) Histogram(name string, opts ...MetricOption) Histogram {
 	return NoOpHistogram{}
 }
+func (NoOpMonitor) Gauge(name string, opts ...MetricOption) Gauge {
+	return NoOpGauge{}
+}
+func (NoOpMonitor) UpDownCounter(name string, opts ...MetricOption) UpDownCounter {
+	return NoOpUpDownCounter{}
+}
+func (NoOpMonitor) ObservableGauge(name string, callback func(ctx context.Context) float64, opts ...MetricOption) {
+}
 
 type NoOpCounter struct{}
 
@@ -28,3 +37,11 @@ func (NoOpCounter) Add(ctx context.Context, delta float64, attrs ...Attribute) {
 type NoOpHistogram struct{}
 
 func (NoOpHistogram) Record(ctx context.Context, value float64, attrs ...Attribute) {}
+
+type NoOpGauge struct{}
+
+func (NoOpGauge) Set(ctx context.Context, value float64, attrs ...Attribute) {}
+
+type NoOpUpDownCounter struct{}
+
+func (NoOpUpDownCounter) Add(ctx context.Context, delta float64, attrs ...Attribute) {}
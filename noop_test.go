@@ -26,4 +26,7 @@ func TestNoOpMonitor(t *testing.T) {
 
 	h := m.Histogram("h")
 	h.Record(ctx, 1)
+
+	m.Gauge("g").Set(ctx, 1)
+	m.UpDownCounter("u").Add(ctx, -1)
 }
@@ -0,0 +1,24 @@
+package owltest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/myuser/owl"
+	"github.com/myuser/owl/owltest"
+)
+
+func ExampleNewTracerProvider() {
+	tp := owltest.NewTracerProvider()
+	owl.SetTracerProvider(tp)
+	defer owl.SetTracerProvider(nil)
+
+	_, end := owl.Start(context.Background(), "charge")
+	err := errors.New("card declined")
+	end(&err)
+
+	span := tp.SpanByName("charge")
+	fmt.Println(span.Status().Code, span.Status().Description)
+	// Output: Error card declined
+}
@@ -3,7 +3,10 @@ package owltest
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+
+	"github.com/myuser/owl"
 )
 
 func TestOwlHelpers(t *testing.T) {
@@ -51,7 +54,129 @@ func TestOwlHelpers(t *testing.T) {
 
 	h := monitor.Histogram("h")
 	h.Record(ctx, 10)
+	h.Record(ctx, 5)
+
+	if got := monitor.GetHistogram("h"); len(got) != 2 || got[0] != 10 || got[1] != 5 {
+		t.Errorf("Histogram samples mismatch, got %v", got)
+	}
+	if monitor.HistogramCount("h") != 2 || monitor.HistogramSum("h") != 15 {
+		t.Errorf("Histogram count/sum mismatch, got %d/%v", monitor.HistogramCount("h"), monitor.HistogramSum("h"))
+	}
+
+	monitor.Gauge("g").Set(ctx, 3)
+	monitor.Gauge("g").Set(ctx, 7)
+	if monitor.GetGauge("g") != 7 {
+		t.Errorf("Gauge mismatch, got %v", monitor.GetGauge("g"))
+	}
+
+	u := monitor.UpDownCounter("u")
+	u.Add(ctx, 2)
+	u.Add(ctx, -1)
+	if monitor.GetGauge("u") != 1 {
+		t.Errorf("UpDownCounter mismatch, got %v", monitor.GetGauge("u"))
+	}
 
 	// Helper methods coverage
 	// monitor.Inc("c2", nil) // Removed as it doesn't exist on TestMonitor directly
 }
+
+func TestLoggerQueries(t *testing.T) {
+	logger := NewLogger()
+	ctx := context.Background()
+
+	logger.Info(ctx, "started")
+	logger.Error(ctx, "failed", errors.New("a"))
+	logger.Error(ctx, "failed again", errors.New("b"))
+
+	if n := logger.CountLevel("ERROR"); n != 2 {
+		t.Errorf("Expected 2 errors, got %d", n)
+	}
+	if n := logger.CountLevel("WARN"); n != 0 {
+		t.Errorf("Expected 0 warnings, got %d", n)
+	}
+
+	entries := logger.EntriesAtLevel("ERROR")
+	if len(entries) != 2 || entries[1].Msg != "failed again" {
+		t.Errorf("Unexpected entries: %+v", entries)
+	}
+
+	if e := logger.Find("started"); e == nil || e.Level != "INFO" {
+		t.Errorf("Find failed: %+v", e)
+	}
+	if logger.Find("missing") != nil {
+		t.Error("Expected nil for missing msg")
+	}
+}
+
+func TestMonitorCounterAttributes(t *testing.T) {
+	monitor := NewMonitor()
+	ctx := context.Background()
+	c := monitor.Counter("http_requests_total")
+
+	c.Inc(ctx, owl.Attr("method", "GET"), owl.Attr("status", "200"))
+	c.Inc(ctx, owl.Attr("status", "200"), owl.Attr("method", "GET"))
+	c.Inc(ctx, owl.Attr("method", "GET"), owl.Attr("status", "500"))
+
+	if got := monitor.GetCounterWith("http_requests_total", owl.Attr("method", "GET"), owl.Attr("status", "200")); got != 2 {
+		t.Errorf("Expected 2 for status=200, got %v", got)
+	}
+	if got := monitor.GetCounterWith("http_requests_total", owl.Attr("status", "500"), owl.Attr("method", "GET")); got != 1 {
+		t.Errorf("Expected 1 for status=500, got %v", got)
+	}
+	if got := monitor.GetCounterWith("http_requests_total", owl.Attr("status", "500")); got != 0 {
+		t.Errorf("Expected 0 for a partial attribute set, got %v", got)
+	}
+	if got := monitor.GetCounter("http_requests_total"); got != 3 {
+		t.Errorf("Expected aggregate 3, got %v", got)
+	}
+}
+
+func TestTestLogger_With(t *testing.T) {
+	logger := NewLogger()
+	bound := logger.With("service", "billing").With("tenant", "acme")
+
+	bound.Info(context.Background(), "charged", "amount", 10)
+
+	entry := logger.LastEntry()
+	if entry == nil || entry.Msg != "charged" {
+		t.Fatalf("Expected entry on the parent logger, got %v", entry)
+	}
+	want := []any{"service", "billing", "tenant", "acme", "amount", 10}
+	if fmt.Sprint(entry.Args) != fmt.Sprint(want) {
+		t.Errorf("Args = %v, want %v", entry.Args, want)
+	}
+}
+
+func TestTestLogger_FieldAsserts(t *testing.T) {
+	logger := NewLogger()
+	ctx := context.Background()
+
+	logger.Info(ctx, "request_success", "status", 200)
+	logger.With("status", 0).Error(ctx, "request_failed", errors.New("boom"), "status", 500, 42)
+
+	if !logger.HasEntry("ERROR", "request_failed") || logger.HasEntry("ERROR", "request_success") {
+		t.Error("HasEntry mismatch")
+	}
+	entry := logger.FindEntry(func(e LogEntry) bool {
+		status, _ := e.Field("status")
+		return status == 500
+	})
+	if entry == nil || entry.Msg != "request_failed" {
+		t.Fatalf("Expected to find the entry with status=500, got %v", entry)
+	}
+	if _, ok := entry.Field("missing"); ok {
+		t.Error("Expected missing field to be absent")
+	}
+}
+
+func TestTestMonitor_ObservableGauge(t *testing.T) {
+	monitor := NewMonitor()
+	monitor.ObservableGauge("queue_depth", func(ctx context.Context) float64 { return 3 })
+
+	if v, ok := monitor.ObserveGauge("queue_depth"); !ok || v != 3 {
+		t.Errorf("ObserveGauge() = %v, %v; want 3, true", v, ok)
+	}
+	if _, ok := monitor.ObserveGauge("missing"); ok {
+		t.Error("Expected unregistered gauge to be reported missing")
+	}
+}
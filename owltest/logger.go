@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"sync"
+
+	"github.com/myuser/owl"
 )
 
 // LogEntry captures a log event.
@@ -14,6 +16,21 @@ type LogEntry struct {
 	Args  []any
 }
 
+// Field returns the value logged for key. If key appears more than once
+// (e.g. bound with With and passed again), the last value wins.
+func (e *LogEntry) Field(key string) (any, bool) {
+	var (
+		val   any
+		found bool
+	)
+	for i := 0; i+1 < len(e.Args); i += 2 {
+		if k, ok := e.Args[i].(string); ok && k == key {
+			val, found = e.Args[i+1], true
+		}
+	}
+	return val, found
+}
+
 // TestLogger is a mock logger that captures logs in memory.
 type TestLogger struct {
 	mu      sync.Mutex
@@ -52,6 +69,42 @@ func (l *TestLogger) Error(ctx context.Context, msg string, err error, args ...a
 	l.log("ERROR", msg, err, args...)
 }
 
+// With returns a logger that records into l, prepending args to the Args of
+// every entry. Entries logged through it are visible on l.
+func (l *TestLogger) With(args ...any) owl.Logger {
+	return &boundLogger{root: l, bound: args}
+}
+
+// boundLogger is a TestLogger view with bound fields.
+type boundLogger struct {
+	root  *TestLogger
+	bound []any
+}
+
+func (b *boundLogger) args(args []any) []any {
+	return append(b.bound[:len(b.bound):len(b.bound)], args...)
+}
+
+func (b *boundLogger) Debug(ctx context.Context, msg string, args ...any) {
+	b.root.log("DEBUG", msg, nil, b.args(args)...)
+}
+
+func (b *boundLogger) Info(ctx context.Context, msg string, args ...any) {
+	b.root.log("INFO", msg, nil, b.args(args)...)
+}
+
+func (b *boundLogger) Warn(ctx context.Context, msg string, args ...any) {
+	b.root.log("WARN", msg, nil, b.args(args)...)
+}
+
+func (b *boundLogger) Error(ctx context.Context, msg string, err error, args ...any) {
+	b.root.log("ERROR", msg, err, b.args(args)...)
+}
+
+func (b *boundLogger) With(args ...any) owl.Logger {
+	return &boundLogger{root: b.root, bound: b.args(args)}
+}
+
 // LastEntry returns the most recent log entry, or nil if empty.
 func (l *TestLogger) LastEntry() *LogEntry {
 	l.mu.Lock()
@@ -62,6 +115,55 @@ func (l *TestLogger) LastEntry() *LogEntry {
 	return &l.Entries[len(l.Entries)-1]
 }
 
+// Find returns the first entry with the given message, or nil if none matches.
+func (l *TestLogger) Find(msg string) *LogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i := range l.Entries {
+		if l.Entries[i].Msg == msg {
+			return &l.Entries[i]
+		}
+	}
+	return nil
+}
+
+// FindEntry returns the first entry matching pred, or nil if none matches.
+func (l *TestLogger) FindEntry(pred func(LogEntry) bool) *LogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i := range l.Entries {
+		if pred(l.Entries[i]) {
+			return &l.Entries[i]
+		}
+	}
+	return nil
+}
+
+// HasEntry reports whether an entry with the given level (e.g. "ERROR") and message was logged.
+func (l *TestLogger) HasEntry(level, msg string) bool {
+	return l.FindEntry(func(e LogEntry) bool {
+		return e.Level == level && e.Msg == msg
+	}) != nil
+}
+
+// EntriesAtLevel returns a copy of the entries logged at level (e.g. "ERROR").
+func (l *TestLogger) EntriesAtLevel(level string) []LogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var res []LogEntry
+	for _, e := range l.Entries {
+		if e.Level == level {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// CountLevel returns the number of entries logged at level.
+func (l *TestLogger) CountLevel(level string) int {
+	return len(l.EntriesAtLevel(level))
+}
+
 // Reset clears the log entries.
 func (l *TestLogger) Reset() {
 	l.mu.Lock()
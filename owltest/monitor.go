@@ -2,6 +2,8 @@ package owltest
 
 import (
 	"context"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/myuser/owl"
@@ -9,14 +11,28 @@ import (
 
 // TestMonitor is a mock monitor that captures metrics in memory.
 type TestMonitor struct {
-	mu       sync.Mutex
-	Counters map[string]float64
+	mu         sync.Mutex
+	Counters   map[string]float64
+	Gauges     map[string]float64   // Last value set; UpDownCounters accumulate here too
+	Histograms map[string][]float64 // Recorded samples in order
+
+	// counterSeries and gaugeSeries hold values per canonical attribute set.
+	counterSeries map[string]map[string]float64
+	gaugeSeries   map[string]map[string]float64
+
+	observables map[string]func(ctx context.Context) float64
 }
 
 // NewMonitor creates a new TestMonitor.
 func NewMonitor() *TestMonitor {
 	return &TestMonitor{
-		Counters: make(map[string]float64),
+		Counters:   make(map[string]float64),
+		Gauges:     make(map[string]float64),
+		Histograms: make(map[string][]float64),
+
+		counterSeries: make(map[string]map[string]float64),
+		gaugeSeries:   make(map[string]map[string]float64),
+		observables:   make(map[string]func(ctx context.Context) float64),
 	}
 }
 
@@ -34,13 +50,104 @@ func (m *TestMonitor) Histogram(name string, opts ...owl.MetricOption) owl.Histo
 	}
 }
 
-// GetCounter returns the current value of a counter.
+func (m *TestMonitor) Gauge(name string, opts ...owl.MetricOption) owl.Gauge {
+	return &testGauge{
+		name: name,
+		m:    m,
+	}
+}
+
+func (m *TestMonitor) UpDownCounter(name string, opts ...owl.MetricOption) owl.UpDownCounter {
+	return &testGauge{
+		name: name,
+		m:    m,
+	}
+}
+
+// ObservableGauge records callback; ObserveGauge invokes it.
+func (m *TestMonitor) ObservableGauge(name string, callback func(ctx context.Context) float64, opts ...owl.MetricOption) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.observables[name] = callback
+}
+
+// ObserveGauge invokes the callback registered for an observable gauge and
+// returns its value. ok is false if no gauge with that name was registered.
+func (m *TestMonitor) ObserveGauge(name string) (value float64, ok bool) {
+	m.mu.Lock()
+	callback, ok := m.observables[name]
+	m.mu.Unlock()
+	if !ok {
+		return 0, false
+	}
+	return callback(context.Background()), true
+}
+
+// GetGauge returns the current value of a gauge or up-down counter.
+func (m *TestMonitor) GetGauge(name string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.Gauges[name]
+}
+
+// GetGaugeWith returns the value of a gauge or up-down counter for exactly the
+// given attribute set. Attribute order does not matter.
+func (m *TestMonitor) GetGaugeWith(name string, attrs ...owl.Attribute) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.gaugeSeries[name][attrKey(attrs)]
+}
+
+// GetHistogram returns a copy of the samples recorded on a histogram.
+func (m *TestMonitor) GetHistogram(name string) []float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]float64(nil), m.Histograms[name]...)
+}
+
+// HistogramCount returns the number of samples recorded on a histogram.
+func (m *TestMonitor) HistogramCount(name string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.Histograms[name])
+}
+
+// HistogramSum returns the sum of the samples recorded on a histogram.
+func (m *TestMonitor) HistogramSum(name string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var sum float64
+	for _, v := range m.Histograms[name] {
+		sum += v
+	}
+	return sum
+}
+
+// GetCounter returns the current value of a counter, summed across all attribute sets.
 func (m *TestMonitor) GetCounter(name string) float64 {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	return m.Counters[name]
 }
 
+// GetCounterWith returns the value of a counter for exactly the given attribute set.
+// Attribute order does not matter.
+func (m *TestMonitor) GetCounterWith(name string, attrs ...owl.Attribute) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counterSeries[name][attrKey(attrs)]
+}
+
+// attrKey returns a canonical, order-independent representation of attrs.
+func attrKey(attrs []owl.Attribute) string {
+	pairs := make([]string, len(attrs))
+	for i, a := range attrs {
+		pairs[i] = a.Key + "=" + a.Value
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
 type testCounter struct {
 	name string
 	m    *TestMonitor
@@ -54,6 +161,18 @@ func (c *testCounter) Add(ctx context.Context, delta float64, attrs ...owl.Attri
 	c.m.mu.Lock()
 	defer c.m.mu.Unlock()
 	c.m.Counters[c.name] += delta
+
+	series(c.m.counterSeries, c.name)[attrKey(attrs)] += delta
+}
+
+// series returns the per-attribute-set values of name, creating them if needed.
+func series(all map[string]map[string]float64, name string) map[string]float64 {
+	s, ok := all[name]
+	if !ok {
+		s = make(map[string]float64)
+		all[name] = s
+	}
+	return s
 }
 
 type testHistogram struct {
@@ -62,8 +181,27 @@ type testHistogram struct {
 }
 
 func (h *testHistogram) Record(ctx context.Context, value float64, attrs ...owl.Attribute) {
-	// For now, histograms in owltest just act as counters summing values? or ignore?
-	// Let's ignore or just log?
-	// The user requirement didn't specify Histogram support in owltest explicit API, but interface needs it.
-	// We'll leave it no-op or simple store if needed later.
+	h.m.mu.Lock()
+	defer h.m.mu.Unlock()
+	h.m.Histograms[h.name] = append(h.m.Histograms[h.name], value)
+}
+
+// testGauge backs both Gauge and UpDownCounter.
+type testGauge struct {
+	name string
+	m    *TestMonitor
+}
+
+func (g *testGauge) Set(ctx context.Context, value float64, attrs ...owl.Attribute) {
+	g.m.mu.Lock()
+	defer g.m.mu.Unlock()
+	g.m.Gauges[g.name] = value
+	series(g.m.gaugeSeries, g.name)[attrKey(attrs)] = value
+}
+
+func (g *testGauge) Add(ctx context.Context, delta float64, attrs ...owl.Attribute) {
+	g.m.mu.Lock()
+	defer g.m.mu.Unlock()
+	g.m.Gauges[g.name] += delta
+	series(g.m.gaugeSeries, g.name)[attrKey(attrs)] += delta
 }
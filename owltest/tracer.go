@@ -0,0 +1,45 @@
+package owltest
+
+import (
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestTracerProvider is a trace.TracerProvider that keeps spans in memory.
+// Install it with owl.SetTracerProvider (or otel.SetTracerProvider).
+type TestTracerProvider struct {
+	*sdktrace.TracerProvider
+	recorder *tracetest.SpanRecorder
+}
+
+// NewTracerProvider creates a TestTracerProvider that samples every span.
+func NewTracerProvider() *TestTracerProvider {
+	sr := tracetest.NewSpanRecorder()
+	return &TestTracerProvider{
+		TracerProvider: sdktrace.NewTracerProvider(
+			sdktrace.WithSampler(sdktrace.AlwaysSample()),
+			sdktrace.WithSpanProcessor(sr),
+		),
+		recorder: sr,
+	}
+}
+
+// RecordedSpans returns the spans ended so far, in the order they ended.
+func (p *TestTracerProvider) RecordedSpans() []sdktrace.ReadOnlySpan {
+	return p.recorder.Ended()
+}
+
+// SpanByName returns the first ended span with the given name, or nil if none matches.
+func (p *TestTracerProvider) SpanByName(name string) sdktrace.ReadOnlySpan {
+	for _, s := range p.recorder.Ended() {
+		if s.Name() == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// Reset clears the recorded spans.
+func (p *TestTracerProvider) Reset() {
+	p.recorder.Reset()
+}
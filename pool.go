@@ -0,0 +1,79 @@
+package owl
+
+import (
+	"context"
+	"sync"
+)
+
+// Pool runs tasks on a fixed number of goroutines with the same panic
+// recovery, logging and "goroutine_panic_total" metric as Go.
+// A Pool is single-use: Submit must not be called after Wait.
+type Pool struct {
+	ctx   context.Context
+	tasks chan func(ctx context.Context)
+	wg    sync.WaitGroup
+}
+
+// NewPool starts size workers bound to ctx. Sizes below 1 are treated as 1.
+// Workers are tracked like goroutines started with Go, so Shutdown waits for
+// them; they exit once Wait is called or ctx is done.
+func NewPool(ctx context.Context, size int) *Pool {
+	if size < 1 {
+		size = 1
+	}
+	p := &Pool{
+		ctx:   ctx,
+		tasks: make(chan func(ctx context.Context)),
+	}
+	p.wg.Add(size)
+	for i := 0; i < size; i++ {
+		goroutines.add()
+		go p.worker()
+	}
+	return p
+}
+
+// Submit hands fn to the next free worker, blocking while all workers are busy.
+// Once ctx is done, tasks are dropped instead of blocking.
+func (p *Pool) Submit(fn func(ctx context.Context)) {
+	select {
+	case p.tasks <- fn:
+	case <-p.ctx.Done():
+	}
+}
+
+// Wait stops accepting tasks and blocks until all submitted tasks have finished.
+func (p *Pool) Wait() {
+	close(p.tasks)
+	p.wg.Wait()
+}
+
+func (p *Pool) worker() {
+	defer goroutines.done()
+	defer p.wg.Done()
+	for {
+		select {
+		case fn, ok := <-p.tasks:
+			if !ok {
+				return
+			}
+			p.run(fn)
+		case <-p.ctx.Done():
+			// Submit drops tasks from now on, so nothing is left to run
+			return
+		}
+	}
+}
+
+// run executes a single task, recovering panics so the worker survives.
+func (p *Pool) run(fn func(ctx context.Context)) {
+	if p.ctx.Err() != nil {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			handlePanic(p.ctx, r)
+		}
+	}()
+	fn(p.ctx)
+}
@@ -0,0 +1,75 @@
+package owl_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/myuser/owl"
+	"github.com/myuser/owl/owltest"
+)
+
+func TestPool(t *testing.T) {
+	owl.SetPanicHandler(nil)
+	monitor := owltest.NewMonitor()
+	owl.SetMonitor(monitor)
+	defer owl.SetMonitor(owl.NoOpMonitor{})
+
+	const workers, tasks = 2, 10
+	pool := owl.NewPool(context.Background(), workers)
+
+	var completed, running, maxRunning atomic.Int32
+	for i := 0; i < tasks; i++ {
+		pool.Submit(func(ctx context.Context) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			completed.Add(1)
+		})
+		if i == 0 {
+			pool.Submit(func(ctx context.Context) { panic("boom") })
+		}
+	}
+	pool.Wait()
+
+	if n := completed.Load(); n != tasks {
+		t.Errorf("Expected %d completed tasks, got %d", tasks, n)
+	}
+	if n := maxRunning.Load(); n > workers {
+		t.Errorf("Expected at most %d concurrent tasks, got %d", workers, n)
+	}
+	if got := monitor.GetCounter("goroutine_panic_total"); got != 1 {
+		t.Errorf("Expected 1 panic recorded, got %v", got)
+	}
+}
+
+func TestPool_Shutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	pool := owl.NewPool(ctx, 2)
+
+	var finished atomic.Bool
+	started := make(chan struct{})
+	pool.Submit(func(ctx context.Context) {
+		close(started)
+		time.Sleep(10 * time.Millisecond)
+		finished.Store(true)
+	})
+	<-started
+
+	// Shutdown waits for the running task; idle workers exit with ctx
+	cancel()
+	if err := owl.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected clean shutdown, got %v", err)
+	}
+	if !finished.Load() {
+		t.Error("Expected Shutdown to wait for the running pool task")
+	}
+	pool.Wait()
+}
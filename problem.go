@@ -1,9 +1,18 @@
 package owl
 
 import (
+	"context"
 	"errors"
+	"runtime"
 )
 
+// owlPkgPrefix identifies frames from this package; the leading ones, from the
+// helpers capturing a stack, are dropped from stack traces.
+const owlPkgPrefix = "github.com/myuser/owl."
+
+// maxStackDepth bounds the number of frames captured by WithStack.
+const maxStackDepth = 32
+
 // Option defines the functional option pattern for errors.
 type Option func(*Error)
 
@@ -21,6 +30,30 @@ func Problem(code Code, opts ...Option) *Error {
 	return e
 }
 
+// Wrap returns a new Error wrapping err, for adding an op or details to an error
+// from a lower layer. Code and SafeMsg are taken from the first *Error in err's
+// chain; for other errors the code is derived with CodeFromError (Internal, or
+// DeadlineExceeded / Canceled for context errors). opts are applied afterwards
+// and can override both. Wrap returns nil if err is nil.
+//
+//	return owl.Wrap(err, owl.WithOp("Order.Create"), owl.WithField("order_id", id))
+func Wrap(err error, opts ...Option) *Error {
+	if err == nil {
+		return nil
+	}
+	e := &Error{
+		Code: CodeFromError(err),
+		Err:  err,
+	}
+	if inner, ok := AsError(err); ok {
+		e.SafeMsg = inner.SafeMsg
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
 // WithMsg sets the internal debug message.
 func WithMsg(msg string) Option {
 	return func(e *Error) {
@@ -42,6 +75,45 @@ func WithOp(op string) Option {
 	}
 }
 
+// WithContext fills Op from the operation stored in ctx (see WithOperation).
+// An explicit WithOp always takes precedence, regardless of option order.
+func WithContext(ctx context.Context) Option {
+	return func(e *Error) {
+		if e.Op == "" {
+			e.Op = OperationFromContext(ctx)
+		}
+	}
+}
+
+// WithType sets the RFC 7807 problem type URI.
+func WithType(uri string) Option {
+	return func(e *Error) {
+		e.Type = uri
+	}
+}
+
+// WithTitle sets the RFC 7807 problem title.
+func WithTitle(title string) Option {
+	return func(e *Error) {
+		e.Title = title
+	}
+}
+
+// WithInstance sets the RFC 7807 instance URI.
+func WithInstance(uri string) Option {
+	return func(e *Error) {
+		e.Instance = uri
+	}
+}
+
+// WithHTTPStatus forces the HTTP status of the error (e.g. 422 for an Invalid
+// error), leaving Code, and so the gRPC mapping, unchanged.
+func WithHTTPStatus(status int) Option {
+	return func(e *Error) {
+		e.HTTPStatus = status
+	}
+}
+
 // WithErr wraps an underlying error.
 // If an error is already wrapped, it joins them (Go 1.20+ behavior).
 func WithErr(err error) Option {
@@ -54,7 +126,18 @@ func WithErr(err error) Option {
 	}
 }
 
-// WithDetails adds contextual details.
+// WithStack captures the call stack at creation time, retrievable via Error.StackTrace.
+// It is opt-in because capturing frames allocates.
+func WithStack() Option {
+	return func(e *Error) {
+		pcs := make([]uintptr, maxStackDepth)
+		n := runtime.Callers(2, pcs)
+		e.stack = pcs[:n]
+	}
+}
+
+// WithDetails adds internal contextual details. They are logged by the
+// middleware but never sent to clients; use WithSafeDetails for those.
 func WithDetails(details map[string]any) Option {
 	return func(e *Error) {
 		if e.Details == nil {
@@ -66,6 +149,30 @@ func WithDetails(details map[string]any) Option {
 	}
 }
 
+// WithField adds a single internal contextual detail, merging like WithDetails.
+func WithField(key string, value any) Option {
+	return func(e *Error) {
+		if e.Details == nil {
+			e.Details = make(map[string]any)
+		}
+		e.Details[key] = value
+	}
+}
+
+// WithSafeDetails adds details that are safe to show to clients, e.g. which
+// field failed validation. They are sent as "details" in HTTP responses and as
+// a gRPC status detail.
+func WithSafeDetails(details map[string]any) Option {
+	return func(e *Error) {
+		if e.SafeDetails == nil {
+			e.SafeDetails = make(map[string]any)
+		}
+		for k, v := range details {
+			e.SafeDetails[k] = v
+		}
+	}
+}
+
 // Legacy-like helper to make simple errors easier?
 // The user asked specifically for: owl.Problem(owl.NotFound, "not found")
 // This implies mixed variadic arguments OR that the second arg is `any` and checks type.
@@ -90,6 +197,10 @@ func New(code Code, args ...any) *Error {
 				// This implies implicit Message setting.
 				e.Msg = v // Overwrite or append? Overwrite seems standard.
 			}
+		case context.Context:
+			if e.Op == "" {
+				e.Op = OperationFromContext(v)
+			}
 		case error:
 			e.Err = v
 		}
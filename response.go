@@ -0,0 +1,113 @@
+package owl
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxErrorBody bounds how much of an error body FromResponse reads.
+// 64KB is sufficient for any reasonable error JSON.
+const maxErrorBody = 64 * 1024
+
+// FromResponse converts an error response (status >= 400) into an *Error, and
+// returns nil for any other status. An owl JSON error body is decoded as is;
+// otherwise the code comes from the status (see FromHTTPStatus) and a bounded
+// excerpt of the body becomes Msg.
+//
+// What is read from the body is put back, so callers can still read it, and
+// the body remains the caller's to close. A body that was already consumed is
+// simply treated as empty.
+func FromResponse(resp *http.Response) *Error {
+	if resp.StatusCode < 400 {
+		return nil
+	}
+
+	var body []byte
+	if resp.Body != nil {
+		body, _ = io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		// Put back what we consumed, ahead of the unread rest
+		resp.Body = &compositeReadCloser{
+			Reader: io.MultiReader(bytes.NewReader(body), resp.Body),
+			Closer: resp.Body,
+		}
+	}
+
+	if isJSONContentType(resp.Header.Get("Content-Type")) || (len(body) > 0 && body[0] == '{') {
+		var e Error
+		if err := json.Unmarshal(body, &e); err == nil && e.Code != 0 {
+			return &e
+		}
+	}
+
+	// If body is text, include a bounded excerpt in the Msg for debugging
+	return Problem(
+		FromHTTPStatus(resp.StatusCode),
+		WithMsg(bodyExcerpt(body, int(maxBodyCapture.Load()))),
+	)
+}
+
+// maxBodyCapture bounds how much of a non-JSON error body ends up in Msg.
+var maxBodyCapture atomic.Int64
+
+func init() {
+	maxBodyCapture.Store(1024)
+}
+
+// SetMaxBodyCapture sets how many bytes of a non-JSON error body FromResponse
+// keeps in the error Msg (default 1KB). Longer bodies, such as HTML error pages,
+// are truncated with an ellipsis.
+func SetMaxBodyCapture(n int) {
+	maxBodyCapture.Store(int64(n))
+}
+
+// bodyExcerpt returns body with control characters removed (whitespace becomes
+// a space), truncated to at most max bytes on a rune boundary.
+func bodyExcerpt(body []byte, max int) string {
+	var sb strings.Builder
+	for _, r := range string(body) {
+		if unicode.IsControl(r) {
+			if !unicode.IsSpace(r) {
+				continue
+			}
+			r = ' '
+		}
+		if sb.Len()+utf8.RuneLen(r) > max {
+			sb.WriteString("…")
+			break
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// isJSONContentType reports whether ct is application/json or a +json type
+// such as application/problem+json, ignoring case and parameters.
+func isJSONContentType(ct string) bool {
+	mediaType, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" ||
+		(strings.HasPrefix(mediaType, "application/") && strings.HasSuffix(mediaType, "+json"))
+}
+
+// compositeReadCloser combines a Reader (for the restored body) and a Closer (the original body).
+type compositeReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func (c *compositeReadCloser) Read(p []byte) (n int, err error) {
+	return c.Reader.Read(p)
+}
+
+func (c *compositeReadCloser) Close() error {
+	return c.Closer.Close()
+}
@@ -0,0 +1,72 @@
+package owl
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestFromResponse(t *testing.T) {
+	newResp := func(status int, ct, body string) *http.Response {
+		resp := &http.Response{StatusCode: status, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(body))}
+		if ct != "" {
+			resp.Header.Set("Content-Type", ct)
+		}
+		return resp
+	}
+
+	t.Run("json body", func(t *testing.T) {
+		body := `{"code":"NOT_FOUND","message":"user not found"}`
+		resp := newResp(http.StatusNotFound, "application/problem+json", body)
+		e := FromResponse(resp)
+		if e == nil || e.Code != CodeNotFound || e.Msg != "user not found" {
+			t.Fatalf("Expected NotFound decoded from the body, got %+v", e)
+		}
+		if restored, _ := io.ReadAll(resp.Body); string(restored) != body {
+			t.Errorf("Expected the body to be restored, got %q", restored)
+		}
+	})
+
+	t.Run("text body", func(t *testing.T) {
+		e := FromResponse(newResp(http.StatusServiceUnavailable, "text/plain", "upstream\nis down"))
+		if e == nil || e.Code != CodeUnavailable || e.Msg != "upstream is down" {
+			t.Fatalf("Expected Unavailable with the body as Msg, got %+v", e)
+		}
+	})
+
+	t.Run("consumed body", func(t *testing.T) {
+		resp := newResp(http.StatusBadGateway, "", "")
+		resp.Body = nil
+		if e := FromResponse(resp); e == nil || e.Code != CodeInternal || e.Msg != "" {
+			t.Fatalf("Expected Internal from the status alone, got %+v", e)
+		}
+	})
+
+	t.Run("no content", func(t *testing.T) {
+		if e := FromResponse(newResp(http.StatusNoContent, "", "")); e != nil {
+			t.Errorf("Expected nil for 204, got %+v", e)
+		}
+	})
+}
+
+func TestIsJSONContentType(t *testing.T) {
+	tests := []struct {
+		ct   string
+		want bool
+	}{
+		{"application/json", true},
+		{"application/json; charset=utf-8", true},
+		{"Application/JSON", true},
+		{"application/problem+json", true},
+		{"application/problem+json; charset=utf-8", true},
+		{"text/plain", false},
+		{"application/jsonp", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isJSONContentType(tt.ct); got != tt.want {
+			t.Errorf("isJSONContentType(%q) = %v, want %v", tt.ct, got, tt.want)
+		}
+	}
+}
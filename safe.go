@@ -4,53 +4,149 @@ import (
 	"context"
 	"fmt"
 	"runtime/debug"
+	"sync"
+	"sync/atomic"
 )
 
 // PanicHandler is a function that handles panics.
 type PanicHandler func(ctx context.Context, r any)
 
-var panicHandler PanicHandler
+var (
+	panicHandlersMu sync.Mutex   // Serializes writers
+	panicHandlers   atomic.Value // Stores []PanicHandler, replaced on write
+)
 
-// SetPanicHandler sets a global panic handler.
+// SetPanicHandler replaces all panic handlers with h. Passing nil removes them.
 func SetPanicHandler(h PanicHandler) {
-	panicHandler = h
+	panicHandlersMu.Lock()
+	defer panicHandlersMu.Unlock()
+	if h == nil {
+		panicHandlers.Store([]PanicHandler(nil))
+		return
+	}
+	panicHandlers.Store([]PanicHandler{h})
+}
+
+// AddPanicHandler appends h to the panic handlers, which are called in the
+// order they were added, so independent parts of an application (metrics,
+// alerting) can each register one.
+func AddPanicHandler(h PanicHandler) {
+	if h == nil {
+		return
+	}
+	panicHandlersMu.Lock()
+	defer panicHandlersMu.Unlock()
+	current, _ := panicHandlers.Load().([]PanicHandler)
+	next := make([]PanicHandler, len(current), len(current)+1)
+	copy(next, current)
+	panicHandlers.Store(append(next, h))
 }
 
-// Go starts a safe goroutine.
+// Go starts a safe goroutine. Shutdown waits for it to return.
 func Go(ctx context.Context, fn func(ctx context.Context)) {
 	// Check context before starting to avoid unnecessary goroutine spawn if already cancelled
 	if ctx.Err() != nil {
 		return
 	}
+	goroutines.add()
 	go func() {
+		defer goroutines.done()
 		// Double-check inside in case of race during spawn
 		if ctx.Err() != nil {
 			return
 		}
 		defer func() {
 			if r := recover(); r != nil {
-				stack := string(debug.Stack())
-
-				// Log the panic
-				// SAFEGUARD: If logger itself panics or is nil (though initialized in init), ensure we don't crash again.
-				// We assume GetLogger() is safe as per current globals.go, but a defer here is good practice.
-				func() {
-					defer func() { recover() }() // Swallow panic during logging
-					GetLogger().Error(ctx, "goroutine_panic", nil,
-						"panic", fmt.Sprintf("%v", r),
-						"stack", stack,
-					)
-				}()
-
-				// Metric
-				GetMonitor().Counter("goroutine_panic_total").Inc(ctx)
-
-				// User handler
-				if panicHandler != nil {
-					panicHandler(ctx, r)
-				}
+				handlePanic(ctx, r)
 			}
 		}()
 		fn(ctx)
 	}()
 }
+
+// GoWithSpan is like Go but runs fn under a new span named name, a child of the
+// span in ctx, so background work shows up in the caller's trace. A panic is
+// recorded on the span and logged with its trace context.
+// It is opt-in to keep Go free of tracing overhead on hot paths.
+func GoWithSpan(ctx context.Context, name string, fn func(ctx context.Context)) {
+	if ctx.Err() != nil {
+		return
+	}
+	goroutines.add()
+	go func() {
+		defer goroutines.done()
+		if ctx.Err() != nil {
+			return
+		}
+		ctx, end := Start(ctx, name)
+		defer func() {
+			var err error
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+				handlePanic(ctx, r)
+			}
+			end(&err)
+		}()
+		fn(ctx)
+	}()
+}
+
+// GoErr starts a safe goroutine and reports its outcome on the returned channel.
+// The channel receives exactly one value and is then closed: the error returned by fn,
+// an Internal *Error (with stack) if fn panicked, or ctx.Err() if ctx was already done.
+// Panics are also logged and counted like in Go.
+func GoErr(ctx context.Context, fn func(ctx context.Context) error) <-chan error {
+	done := make(chan error, 1)
+	if err := ctx.Err(); err != nil {
+		done <- err
+		close(done)
+		return done
+	}
+	goroutines.add()
+	go func() {
+		defer goroutines.done()
+		defer close(done)
+		defer func() {
+			if r := recover(); r != nil {
+				err := Problem(Internal,
+					WithMsg("goroutine panic"),
+					WithErr(fmt.Errorf("panic: %v", r)),
+					WithStack(),
+				)
+				handlePanic(ctx, r)
+				done <- err
+			}
+		}()
+		done <- fn(ctx)
+	}()
+	return done
+}
+
+// handlePanic logs and counts a recovered panic, then calls the PanicHandler.
+func handlePanic(ctx context.Context, r any) {
+	stack := string(debug.Stack())
+
+	// Log the panic
+	// SAFEGUARD: If logger itself panics or is nil (though initialized in init), ensure we don't crash again.
+	// We assume GetLogger() is safe as per current globals.go, but a defer here is good practice.
+	func() {
+		defer func() { recover() }() // Swallow panic during logging
+		GetLogger().Error(ctx, "goroutine_panic", nil,
+			"panic", fmt.Sprintf("%v", r),
+			"stack", stack,
+		)
+	}()
+
+	// Metric
+	GetMonitor().Counter("goroutine_panic_total").Inc(ctx)
+
+	// User handlers, each guarded so one failing handler doesn't skip the rest.
+	// The panic is already logged above, whatever the handlers do.
+	handlers, _ := panicHandlers.Load().([]PanicHandler)
+	for _, h := range handlers {
+		func() {
+			defer func() { recover() }()
+			h(ctx, r)
+		}()
+	}
+}
@@ -2,11 +2,16 @@ package owl_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/myuser/owl"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestGo_ContextCancelled(t *testing.T) {
@@ -76,3 +81,128 @@ func TestGo_PanicRecovery(t *testing.T) {
 		t.Fatal("Timeout waiting for panic handler")
 	}
 }
+
+func TestAddPanicHandler(t *testing.T) {
+	owl.SetPanicHandler(nil)
+	defer owl.SetPanicHandler(nil)
+
+	var calls []string
+	owl.AddPanicHandler(func(ctx context.Context, r any) {
+		calls = append(calls, "metrics")
+		panic("handler bug") // Must not stop the chain
+	})
+	owl.AddPanicHandler(func(ctx context.Context, r any) {
+		calls = append(calls, fmt.Sprint("alerting:", r))
+	})
+
+	if err := <-owl.GoErr(context.Background(), func(ctx context.Context) error {
+		panic("boom")
+	}); err == nil {
+		t.Fatal("Expected panic error")
+	}
+	if len(calls) != 2 || calls[0] != "metrics" || calls[1] != "alerting:boom" {
+		t.Errorf("Expected both handlers in order, got %v", calls)
+	}
+
+	// SetPanicHandler still replaces the whole chain
+	calls = nil
+	owl.SetPanicHandler(func(ctx context.Context, r any) { calls = append(calls, "only") })
+	<-owl.GoErr(context.Background(), func(ctx context.Context) error { panic("again") })
+	if len(calls) != 1 || calls[0] != "only" {
+		t.Errorf("Expected SetPanicHandler to replace handlers, got %v", calls)
+	}
+}
+
+func TestGoErr(t *testing.T) {
+	owl.SetPanicHandler(nil)
+	ctx := context.Background()
+
+	wantErr := errors.New("job failed")
+	if err := <-owl.GoErr(ctx, func(ctx context.Context) error { return wantErr }); err != wantErr {
+		t.Errorf("Expected %v, got %v", wantErr, err)
+	}
+
+	if err := <-owl.GoErr(ctx, func(ctx context.Context) error { return nil }); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+
+	select {
+	case err := <-owl.GoErr(ctx, func(ctx context.Context) error { panic("boom") }):
+		var e *owl.Error
+		if !errors.As(err, &e) || e.Code != owl.Internal {
+			t.Fatalf("Expected Internal *owl.Error, got %v", err)
+		}
+		if e.Err == nil || e.Err.Error() != "panic: boom" {
+			t.Errorf("Expected wrapped panic value, got %v", e.Err)
+		}
+		if len(e.StackTrace()) == 0 {
+			t.Error("Expected panic stack to be captured")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for panic error")
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := <-owl.GoErr(cancelled, func(ctx context.Context) error { return nil }); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+// ctxLogger records the context of each Error call.
+type ctxLogger struct {
+	owl.NoOpLogger
+	errCtx chan context.Context
+}
+
+func (l *ctxLogger) Error(ctx context.Context, msg string, err error, args ...any) {
+	l.errCtx <- ctx
+}
+
+func TestGoWithSpan(t *testing.T) {
+	owl.SetPanicHandler(nil)
+	sr := withSpanRecorder(t)
+	logger := &ctxLogger{errCtx: make(chan context.Context, 1)}
+	owl.SetLogger(logger)
+	defer owl.SetLogger(owl.NoOpLogger{})
+
+	parentCtx, endParent := owl.Start(context.Background(), "request")
+	parent := trace.SpanContextFromContext(parentCtx)
+
+	owl.GoWithSpan(parentCtx, "goroutine", func(ctx context.Context) {
+		panic("boom")
+	})
+
+	var logCtx context.Context
+	select {
+	case logCtx = <-logger.errCtx:
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for panic log")
+	}
+	endParent(nil)
+
+	if got := trace.SpanContextFromContext(logCtx).TraceID(); got != parent.TraceID() {
+		t.Errorf("Expected panic log in trace %s, got %s", parent.TraceID(), got)
+	}
+
+	// The child span is ended after the panic is logged
+	deadline := time.Now().Add(time.Second)
+	for len(sr.Ended()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	var child sdktrace.ReadOnlySpan
+	for _, s := range sr.Ended() {
+		if s.Name() == "goroutine" {
+			child = s
+		}
+	}
+	if child == nil {
+		t.Fatal("Expected goroutine span")
+	}
+	if child.Parent().SpanID() != parent.SpanID() {
+		t.Errorf("Expected goroutine span to be a child of the request span")
+	}
+	if child.Status().Code != codes.Error {
+		t.Errorf("Expected error status on panic, got %v", child.Status())
+	}
+}
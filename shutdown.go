@@ -0,0 +1,67 @@
+package owl
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// goroutines tracks the goroutines started by Go, GoWithSpan and GoErr.
+var goroutines tracker
+
+// tracker counts running goroutines. Unlike a sync.WaitGroup it can be waited
+// on while new goroutines are being started.
+type tracker struct {
+	mu   sync.Mutex
+	n    int
+	idle chan struct{} // Closed when n drops to zero
+}
+
+func (t *tracker) add() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.n == 0 {
+		t.idle = make(chan struct{})
+	}
+	t.n++
+}
+
+func (t *tracker) done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.n--
+	if t.n == 0 {
+		close(t.idle)
+	}
+}
+
+// wait returns a channel closed once no goroutine is running, and the current count.
+func (t *tracker) wait() (<-chan struct{}, int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.n == 0 {
+		idle := make(chan struct{})
+		close(idle)
+		return idle, 0
+	}
+	return t.idle, t.n
+}
+
+// Shutdown waits for the goroutines started with Go, GoWithSpan and GoErr, and
+// the workers of every Pool, to return. If ctx is done first, it returns a DeadlineExceeded *Error wrapping
+// ctx.Err(). Cancel the context passed to those goroutines beforehand so they
+// can stop; goroutines started while Shutdown waits are waited for too.
+func Shutdown(ctx context.Context) error {
+	idle, _ := goroutines.wait()
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		_, n := goroutines.wait()
+		return Problem(DeadlineExceeded,
+			WithMsg(fmt.Sprintf("shutdown: %d goroutine(s) still running", n)),
+			WithErr(ctx.Err()),
+			WithField("goroutines", n),
+		)
+	}
+}
@@ -0,0 +1,46 @@
+package owl_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/myuser/owl"
+)
+
+func TestShutdown(t *testing.T) {
+	release := make(chan struct{})
+	var finished atomic.Int32
+	for i := 0; i < 2; i++ {
+		owl.Go(context.Background(), func(ctx context.Context) {
+			<-release
+			time.Sleep(10 * time.Millisecond)
+			finished.Add(1)
+		})
+	}
+	errCh := owl.GoErr(context.Background(), func(ctx context.Context) error {
+		<-release
+		finished.Add(1)
+		return nil
+	})
+
+	// Deadline hits while the goroutines are still blocked
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := owl.Shutdown(ctx)
+	if !errors.Is(err, owl.DeadlineExceeded) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected DeadlineExceeded, got %v", err)
+	}
+
+	// Shutdown blocks until they complete
+	close(release)
+	if err := owl.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected clean shutdown, got %v", err)
+	}
+	if n := finished.Load(); n != 3 {
+		t.Errorf("Expected all 3 goroutines to finish before Shutdown returned, got %d", n)
+	}
+	<-errCh
+}
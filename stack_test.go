@@ -0,0 +1,48 @@
+package owl_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/myuser/owl"
+)
+
+func TestWithStack(t *testing.T) {
+	if frames := owl.Problem(owl.Internal).StackTrace(); frames != nil {
+		t.Errorf("Expected no stack without WithStack, got %d frames", len(frames))
+	}
+
+	frames := owl.Problem(owl.Internal, owl.WithStack()).StackTrace()
+	if len(frames) == 0 {
+		t.Fatal("Expected captured frames")
+	}
+	if !strings.HasSuffix(frames[0].Function, "TestWithStack") {
+		t.Errorf("Expected first frame to be the caller, got %s", frames[0].Function)
+	}
+
+	frames = owl.New(owl.Internal, "boom", owl.WithStack()).StackTrace()
+	if len(frames) == 0 || !strings.HasSuffix(frames[0].Function, "TestWithStack") {
+		t.Errorf("Expected New to capture the caller, got %v", frames)
+	}
+}
+
+func TestWithStack_KeepsOuterOwlFrames(t *testing.T) {
+	err := <-owl.GoErr(context.Background(), func(ctx context.Context) error {
+		return owl.Problem(owl.Internal, owl.WithStack())
+	})
+	e, _ := owl.AsError(err)
+	if e == nil {
+		t.Fatalf("Expected *owl.Error, got %v", err)
+	}
+	frames := e.StackTrace()
+	if len(frames) == 0 || strings.HasPrefix(frames[0].Function, "github.com/myuser/owl.") {
+		t.Fatalf("Expected the first frame to be the caller, got %v", frames)
+	}
+	for _, f := range frames[1:] {
+		if strings.HasPrefix(f.Function, "github.com/myuser/owl.GoErr") {
+			return
+		}
+	}
+	t.Errorf("Expected the GoErr frame kept, got %v", frames)
+}
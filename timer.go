@@ -0,0 +1,28 @@
+package owl
+
+import (
+	"context"
+	"time"
+)
+
+// Time starts a timer and returns a function that records the elapsed seconds on h.
+//
+// Usage:
+//
+//	defer owl.Time(ctx, latency, owl.Attr("op", "query"))()
+func Time(ctx context.Context, h Histogram, attrs ...Attribute) func() {
+	start := time.Now()
+	return func() {
+		h.Record(ctx, time.Since(start).Seconds(), attrs...)
+	}
+}
+
+// Measure runs fn and records its duration in seconds on the named histogram of m.
+// The error returned by fn is passed through unchanged.
+func Measure(ctx context.Context, m Monitor, name string, fn func(ctx context.Context) error, attrs ...Attribute) error {
+	if m == nil {
+		m = GetMonitor()
+	}
+	defer Time(ctx, m.Histogram(name), attrs...)()
+	return fn(ctx)
+}
@@ -0,0 +1,59 @@
+package owl_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/myuser/owl"
+)
+
+// recordingHistogram captures the last recorded value.
+type recordingHistogram struct {
+	owl.NoOpHistogram
+	value float64
+	calls int
+}
+
+func (h *recordingHistogram) Record(ctx context.Context, value float64, attrs ...owl.Attribute) {
+	h.value = value
+	h.calls++
+}
+
+type recordingMonitor struct {
+	owl.NoOpMonitor
+	h *recordingHistogram
+}
+
+func (m recordingMonitor) Histogram(name string, opts ...owl.MetricOption) owl.Histogram {
+	return m.h
+}
+
+func TestTime(t *testing.T) {
+	h := &recordingHistogram{}
+	stop := owl.Time(context.Background(), h)
+	stop()
+
+	if h.calls != 1 {
+		t.Fatalf("Expected 1 record, got %d", h.calls)
+	}
+	if h.value < 0 {
+		t.Errorf("Expected non-negative duration, got %v", h.value)
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	h := &recordingHistogram{}
+	m := recordingMonitor{h: h}
+	boom := errors.New("boom")
+
+	err := owl.Measure(context.Background(), m, "job_duration_seconds", func(ctx context.Context) error {
+		return boom
+	})
+	if err != boom {
+		t.Errorf("Expected error passthrough, got %v", err)
+	}
+	if h.calls != 1 {
+		t.Errorf("Expected 1 record, got %d", h.calls)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"sync/atomic"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
@@ -13,8 +14,50 @@ import (
 // TracerName is the name of the tracer.
 var tracerName atomic.Value
 
+// tracerProvider stores a tracerProviderHolder.
+var tracerProvider atomic.Value
+
+type tracerProviderHolder struct {
+	tp trace.TracerProvider
+}
+
+// skipSpanOK disables marking successful spans with codes.Ok.
+var skipSpanOK atomic.Bool
+
+// SpanErrorFilter reports whether err should mark a span as errored.
+type SpanErrorFilter func(err error) bool
+
+// spanErrorFilter stores a spanErrorFilterHolder.
+var spanErrorFilter atomic.Value
+
+type spanErrorFilterHolder struct {
+	f SpanErrorFilter
+}
+
 func init() {
 	tracerName.Store("github.com/myuser/owl")
+	spanErrorFilter.Store(spanErrorFilterHolder{})
+	tracerProvider.Store(tracerProviderHolder{})
+}
+
+// SetSpanErrorFilter sets the filter consulted by owl.Start before recording an error.
+// Errors rejected by the filter (e.g. an expected NotFound) are treated as success.
+// Passing nil restores the default of recording every error.
+func SetSpanErrorFilter(f SpanErrorFilter) {
+	spanErrorFilter.Store(spanErrorFilterHolder{f: f})
+}
+
+// shouldRecordSpanError applies the configured SpanErrorFilter.
+func shouldRecordSpanError(err error) bool {
+	f := spanErrorFilter.Load().(spanErrorFilterHolder).f
+	return f == nil || f(err)
+}
+
+// SetSpanOKStatus controls whether owl.Start marks successful spans with codes.Ok.
+// It is enabled by default; disable it when handlers set their own span status.
+// To opt out for a single span, see KeepSpanStatus.
+func SetSpanOKStatus(enabled bool) {
+	skipSpanOK.Store(!enabled)
 }
 
 // SetTracerName sets the name of the tracer used by owl.Start.
@@ -23,45 +66,132 @@ func SetTracerName(name string) {
 	tracerName.Store(name)
 }
 
-// Start starts a new span using the global OTel tracer.
+// SetTracerProvider sets the TracerProvider used by owl.Start.
+// Passing nil restores the default of using the global otel.GetTracerProvider().
+func SetTracerProvider(tp trace.TracerProvider) {
+	tracerProvider.Store(tracerProviderHolder{tp: tp})
+}
+
+// tracer returns the tracer used by owl.Start.
+func tracer() trace.Tracer {
+	tn := tracerName.Load().(string)
+	if tp := tracerProvider.Load().(tracerProviderHolder).tp; tp != nil {
+		return tp.Tracer(tn)
+	}
+	return otel.Tracer(tn)
+}
+
+// Start starts a new span using the configured TracerProvider
+// (see SetTracerProvider), falling back to the global OTel tracer.
 // It returns a context with the span and a function to end it.
 // The returned end function should be deferred, optionally passing a pointer to the error
-// to automatically record it on the span.
+// to automatically record it on the span. On success the span status is set to Ok
+// (see SetSpanOKStatus and KeepSpanStatus).
 //
 // Usage:
 //
 //	ctx, end := owl.Start(ctx, "OperationName")
 //	defer end(&err)
 func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, func(*error)) {
-	// Use the configured tracer name
-	tn := tracerName.Load().(string)
-	tracer := otel.Tracer(tn)
-
-	ctx, span := tracer.Start(ctx, name, opts...)
+	ctx, span := tracer().Start(ctx, name, opts...)
+	keep := new(atomic.Bool)
+	ctx = context.WithValue(ctx, keepSpanStatusKey{}, keep)
 
 	return ctx, func(errPtr *error) {
-		if errPtr != nil && *errPtr != nil {
+		if errPtr != nil && *errPtr != nil && shouldRecordSpanError(*errPtr) {
 			err := *errPtr
 			span.RecordError(err)
 			span.SetStatus(codes.Error, err.Error())
+		} else if !skipSpanOK.Load() && !keep.Load() {
+			span.SetStatus(codes.Ok, "")
 		}
 		span.End()
 	}
 }
 
-// SetBaggage sets a baggage member in the context.
+type keepSpanStatusKey struct{}
+
+// KeepSpanStatus stops the end function of the owl.Start span in ctx from
+// setting codes.Ok on success, e.g. after the handler marked the span as
+// errored itself:
+//
+//	span.SetStatus(codes.Error, "partial failure")
+//	owl.KeepSpanStatus(ctx)
+//	return nil
+//
+// Errors passed to the end function are still recorded. It is a no-op when ctx
+// has no span started by owl.Start.
+func KeepSpanStatus(ctx context.Context) {
+	if keep, ok := ctx.Value(keepSpanStatusKey{}).(*atomic.Bool); ok {
+		keep.Store(true)
+	}
+}
+
+// AddEvent adds an event to the span in ctx.
+// It is a no-op when ctx has no recording span.
+func AddEvent(ctx context.Context, name string, attrs ...Attribute) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	span.AddEvent(name, trace.WithAttributes(toSpanAttrs(attrs)...))
+}
+
+// SetSpanAttributes sets attributes on the span in ctx.
+// It is a no-op when ctx has no recording span.
+func SetSpanAttributes(ctx context.Context, attrs ...Attribute) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	span.SetAttributes(toSpanAttrs(attrs)...)
+}
+
+func toSpanAttrs(attrs []Attribute) []attribute.KeyValue {
+	res := make([]attribute.KeyValue, len(attrs))
+	for i, a := range attrs {
+		res[i] = attribute.String(a.Key, a.Value)
+	}
+	return res
+}
+
+// SetBaggage sets a baggage member in the context, keeping any existing members.
+// A member with the same key is overwritten. Invalid keys or values (see the W3C
+// baggage spec) are logged as a warning and ctx is returned unchanged.
 func SetBaggage(ctx context.Context, key, value string) context.Context {
-	m, _ := baggage.NewMember(key, value)
-	b, _ := baggage.New(m) // Note: This creates new baggage with just this member.
-	// Properly we should add to existing baggage.
-	current := baggage.FromContext(ctx)
-	// baggage.FromContext returns 'Baggage'. 'New' returns 'Baggage'.
-	// To add member... OTel Baggage is immutable.
-	// b, _ = current.SetMember(m) // SetMember returns Baggage, error.
-	b, _ = current.SetMember(m)
+	m, err := baggage.NewMember(key, value)
+	if err != nil {
+		GetLogger().Warn(ctx, "invalid_baggage_member", "key", key, "error", err.Error())
+		return ctx
+	}
+	// Baggage is immutable: SetMember returns a copy with m added or replaced.
+	b, err := baggage.FromContext(ctx).SetMember(m)
+	if err != nil {
+		GetLogger().Warn(ctx, "invalid_baggage_member", "key", key, "error", err.Error())
+		return ctx
+	}
 	return baggage.ContextWithBaggage(ctx, b)
 }
 
+// DeleteBaggage removes a baggage member from the context.
+func DeleteBaggage(ctx context.Context, key string) context.Context {
+	b := baggage.FromContext(ctx)
+	if b.Member(key).Key() == "" {
+		return ctx
+	}
+	return baggage.ContextWithBaggage(ctx, b.DeleteMember(key))
+}
+
+// AllBaggage returns every baggage member in the context, mainly for debugging.
+func AllBaggage(ctx context.Context) map[string]string {
+	members := baggage.FromContext(ctx).Members()
+	res := make(map[string]string, len(members))
+	for _, m := range members {
+		res[m.Key()] = m.Value()
+	}
+	return res
+}
+
 // GetBaggage returns a baggage member value from the context.
 func GetBaggage(ctx context.Context, key string) string {
 	b := baggage.FromContext(ctx)
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/myuser/owl"
+	"github.com/myuser/owl/owltest"
 )
 
 func TestBaggage(t *testing.T) {
@@ -21,3 +22,47 @@ func TestBaggage(t *testing.T) {
 		t.Errorf("Expected empty baggage, got '%s'", val)
 	}
 }
+
+func TestBaggage_SetOverwriteDelete(t *testing.T) {
+	ctx := context.Background()
+	ctx = owl.SetBaggage(ctx, "tenant", "acme")
+	ctx = owl.SetBaggage(ctx, "user_id", "1")
+	ctx = owl.SetBaggage(ctx, "user_id", "2")
+
+	all := owl.AllBaggage(ctx)
+	if len(all) != 2 || all["tenant"] != "acme" || all["user_id"] != "2" {
+		t.Errorf("Unexpected baggage after set/overwrite: %v", all)
+	}
+
+	ctx = owl.DeleteBaggage(ctx, "user_id")
+	if v := owl.GetBaggage(ctx, "user_id"); v != "" {
+		t.Errorf("Expected user_id to be deleted, got %q", v)
+	}
+	if v := owl.GetBaggage(ctx, "tenant"); v != "acme" {
+		t.Errorf("Expected tenant to survive delete, got %q", v)
+	}
+
+	// Deleting a missing key is a no-op
+	if got := owl.DeleteBaggage(ctx, "missing"); got != ctx {
+		t.Error("Expected unchanged context when deleting a missing key")
+	}
+}
+
+func TestBaggage_InvalidKey(t *testing.T) {
+	logger := owltest.NewLogger()
+	owl.SetLogger(logger)
+	defer owl.SetLogger(owl.NoOpLogger{})
+
+	ctx := owl.SetBaggage(context.Background(), "tenant", "acme")
+	got := owl.SetBaggage(ctx, "bad key", "v")
+
+	if got != ctx {
+		t.Error("Expected unchanged context for invalid key")
+	}
+	if len(owl.AllBaggage(got)) != 1 {
+		t.Errorf("Expected existing baggage to be kept, got %v", owl.AllBaggage(got))
+	}
+	if logger.Find("invalid_baggage_member") == nil {
+		t.Error("Expected a warning for the invalid key")
+	}
+}
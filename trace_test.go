@@ -2,11 +2,27 @@ package owl_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/myuser/owl"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// withSpanRecorder installs an in-memory tracer provider for the duration of the test.
+func withSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	sr := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	return sr
+}
+
 func TestSetTracerName(t *testing.T) {
 	// Default name is "github.com/myuser/owl" (from init)
 	// We can't easily check the internal tracer name via public API without reflection or a mock provider.
@@ -21,3 +37,109 @@ func TestSetTracerName(t *testing.T) {
 		t.Error("Start returned nil context")
 	}
 }
+
+func TestStart_Status(t *testing.T) {
+	sr := withSpanRecorder(t)
+
+	_, end := owl.Start(context.Background(), "ok-span")
+	end(nil)
+
+	_, end = owl.Start(context.Background(), "err-span")
+	err := errors.New("boom")
+	end(&err)
+
+	owl.SetSpanOKStatus(false)
+	defer owl.SetSpanOKStatus(true)
+	_, end = owl.Start(context.Background(), "unset-span")
+	end(nil)
+
+	spans := sr.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("Expected 3 spans, got %d", len(spans))
+	}
+	want := []codes.Code{codes.Ok, codes.Error, codes.Unset}
+	for i, span := range spans {
+		if got := span.Status().Code; got != want[i] {
+			t.Errorf("%s: expected status %v, got %v", span.Name(), want[i], got)
+		}
+	}
+}
+
+func TestKeepSpanStatus(t *testing.T) {
+	sr := withSpanRecorder(t)
+
+	ctx, end := owl.Start(context.Background(), "handled")
+	trace.SpanFromContext(ctx).SetStatus(codes.Error, "partial failure")
+	owl.KeepSpanStatus(ctx)
+	end(nil)
+
+	owl.KeepSpanStatus(context.Background()) // No owl span: must not panic
+
+	spans := sr.Ended()
+	if got := spans[0].Status(); got.Code != codes.Error || got.Description != "partial failure" {
+		t.Errorf("Expected the handler's status kept, got %v", got)
+	}
+}
+
+func TestStart_SpanErrorFilter(t *testing.T) {
+	sr := withSpanRecorder(t)
+
+	owl.SetSpanErrorFilter(func(err error) bool {
+		return !errors.Is(err, owl.NotFound)
+	})
+	defer owl.SetSpanErrorFilter(nil)
+
+	_, end := owl.Start(context.Background(), "not-found")
+	err := error(owl.Problem(owl.NotFound))
+	end(&err)
+
+	_, end = owl.Start(context.Background(), "internal")
+	err = owl.Problem(owl.Internal)
+	end(&err)
+
+	spans := sr.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+	if got := spans[0].Status().Code; got == codes.Error {
+		t.Error("Filtered error should not mark span as errored")
+	}
+	if len(spans[0].Events()) != 0 {
+		t.Error("Filtered error should not be recorded")
+	}
+	if got := spans[1].Status().Code; got != codes.Error {
+		t.Errorf("Expected Error status, got %v", got)
+	}
+}
+
+func TestSpanEnrichment(t *testing.T) {
+	sr := withSpanRecorder(t)
+
+	// No span: must not panic
+	owl.AddEvent(context.Background(), "ignored")
+	owl.SetSpanAttributes(context.Background(), owl.Attr("ignored", "true"))
+
+	ctx, end := owl.Start(context.Background(), "enriched")
+	owl.SetSpanAttributes(ctx, owl.Attr("user.id", "42"))
+	owl.AddEvent(ctx, "cache_miss", owl.Attr("key", "user:42"))
+	end(nil)
+
+	spans := sr.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+
+	attrs := span.Attributes()
+	if len(attrs) != 1 || string(attrs[0].Key) != "user.id" || attrs[0].Value.AsString() != "42" {
+		t.Errorf("Unexpected span attributes: %v", attrs)
+	}
+
+	events := span.Events()
+	if len(events) != 1 || events[0].Name != "cache_miss" {
+		t.Fatalf("Unexpected span events: %v", events)
+	}
+	if a := events[0].Attributes; len(a) != 1 || a[0].Value.AsString() != "user:42" {
+		t.Errorf("Unexpected event attributes: %v", a)
+	}
+}
@@ -1,37 +1,59 @@
 package owl
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // Code represents the canonical error code taxonomy.
 type Code uint32
 
+// Each code's value is the HTTP status it maps to. A code sharing its status
+// with another takes the status times ten plus a sequence number (CodeAborted
+// is 4090, the first extra 409), so values stay unique and the status can
+// still be read off them. Codes are serialized by name (see String and
+// UnmarshalJSON), never by value.
 const (
-	CodeUnknown          Code = 0
-	CodeOK               Code = 200
-	CodeInvalid          Code = 400 // Invalid Argument
-	CodeUnauthorized     Code = 401 // Unauthenticated
-	CodePermissionDenied Code = 403 // Permission Denied
-	CodeNotFound         Code = 404 // Not Found
-	CodeInternal         Code = 500 // Internal System Error
-	CodeUnavailable      Code = 503 // Service Unavailable
-	CodeDeadlineExceeded Code = 504 // Timeout
+	CodeUnknown            Code = 0
+	CodeOK                 Code = 200
+	CodeInvalid            Code = 400  // Invalid Argument
+	CodeUnauthorized       Code = 401  // Unauthenticated
+	CodePermissionDenied   Code = 403  // Permission Denied
+	CodeNotFound           Code = 404  // Not Found
+	CodeAlreadyExists      Code = 409  // Conflict
+	CodeFailedPrecondition Code = 412  // Precondition Failed
+	CodeResourceExhausted  Code = 429  // Too Many Requests
+	CodeCanceled           Code = 499  // Client Closed Request
+	CodeAborted            Code = 4090 // Concurrency conflict (409, distinct from AlreadyExists)
+	CodeInternal           Code = 500  // Internal System Error
+	CodeUnavailable        Code = 503  // Service Unavailable
+	CodeDeadlineExceeded   Code = 504  // Timeout
 )
 
 // Aliases for cleaner API usage (owl.NotFound vs owl.CodeNotFound)
 // This matches the user request: owl.Problem(owl.NotFound, ...)
 const (
-	OK               = CodeOK
-	Invalid          = CodeInvalid
-	Unauthorized     = CodeUnauthorized
-	PermissionDenied = CodePermissionDenied
-	NotFound         = CodeNotFound
-	Internal         = CodeInternal
-	Unavailable      = CodeUnavailable
-	DeadlineExceeded = CodeDeadlineExceeded
+	OK                 = CodeOK
+	Invalid            = CodeInvalid
+	Unauthorized       = CodeUnauthorized
+	PermissionDenied   = CodePermissionDenied
+	NotFound           = CodeNotFound
+	AlreadyExists      = CodeAlreadyExists
+	FailedPrecondition = CodeFailedPrecondition
+	ResourceExhausted  = CodeResourceExhausted
+	Canceled           = CodeCanceled
+	Aborted            = CodeAborted
+	Internal           = CodeInternal
+	Unavailable        = CodeUnavailable
+	DeadlineExceeded   = CodeDeadlineExceeded
 )
 
 func (c Code) String() string {
@@ -46,6 +68,16 @@ func (c Code) String() string {
 		return "PERMISSION_DENIED"
 	case CodeNotFound:
 		return "NOT_FOUND"
+	case CodeAlreadyExists:
+		return "ALREADY_EXISTS"
+	case CodeFailedPrecondition:
+		return "FAILED_PRECONDITION"
+	case CodeResourceExhausted:
+		return "RESOURCE_EXHAUSTED"
+	case CodeCanceled:
+		return "CANCELED"
+	case CodeAborted:
+		return "ABORTED"
 	case CodeInternal:
 		return "INTERNAL"
 	case CodeUnavailable:
@@ -79,6 +111,16 @@ func (c *Code) UnmarshalJSON(b []byte) error {
 		*c = CodePermissionDenied
 	case "NOT_FOUND":
 		*c = CodeNotFound
+	case "ALREADY_EXISTS":
+		*c = CodeAlreadyExists
+	case "FAILED_PRECONDITION":
+		*c = CodeFailedPrecondition
+	case "RESOURCE_EXHAUSTED":
+		*c = CodeResourceExhausted
+	case "CANCELED":
+		*c = CodeCanceled
+	case "ABORTED":
+		*c = CodeAborted
 	case "INTERNAL":
 		*c = CodeInternal
 	case "UNAVAILABLE":
@@ -92,13 +134,30 @@ func (c *Code) UnmarshalJSON(b []byte) error {
 }
 
 // Error is the smart error struct.
+// Its JSON form is defined by MarshalJSON and UnmarshalJSON (see SetJSONFields).
 type Error struct {
-	Code    Code           `json:"code"`
-	Msg     string         `json:"message,omitempty"`      // Internal
-	SafeMsg string         `json:"safe_message,omitempty"` // Public
-	Op      string         `json:"op,omitempty"`
-	Err     error          `json:"-"`
-	Details map[string]any `json:"details,omitempty"`
+	Code        Code
+	Msg         string // Internal
+	SafeMsg     string // Public
+	Op          string
+	Err         error
+	Details     map[string]any // Internal: logged, never sent to clients
+	SafeDetails map[string]any // Public: sent as "details" to clients
+
+	// RFC 7807 problem details members
+	Type     string // URI identifying the problem type
+	Title    string // Short human-readable summary of the problem type
+	Instance string // URI identifying this occurrence
+
+	// HTTPStatus overrides the status ToHTTPStatus derives from Code, when non-zero.
+	HTTPStatus int
+
+	stack []uintptr // Program counters captured by WithStack
+}
+
+// IsProblemDetails reports whether any RFC 7807 specific member is set.
+func (e *Error) IsProblemDetails() bool {
+	return e.Type != "" || e.Title != "" || e.Instance != ""
 }
 
 func (e *Error) Error() string {
@@ -108,6 +167,76 @@ func (e *Error) Error() string {
 	return fmt.Sprintf("%s: %s", e.Op, e.Msg)
 }
 
+// StackTrace returns the frames captured by WithStack, starting at the caller
+// of the owl helper that captured them (Problem, New, Wrap...). Later owl
+// frames, such as Go's, are kept. It returns nil if the error was created
+// without WithStack.
+func (e *Error) StackTrace() []runtime.Frame {
+	if len(e.stack) == 0 {
+		return nil
+	}
+	var res []runtime.Frame
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		f, more := frames.Next()
+		if len(res) > 0 || !strings.HasPrefix(f.Function, owlPkgPrefix) {
+			res = append(res, f)
+		}
+		if !more {
+			break
+		}
+	}
+	return res
+}
+
+// AsError returns the first *Error in err's chain, if any.
+// Unlike a type assertion it also finds owl errors wrapped with fmt.Errorf("%w").
+func AsError(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
+
+// Clone returns a copy of e that is safe to modify, e.g. to add per-request
+// details to a shared template error. The Details and SafeDetails maps are
+// deep-copied (nested maps and slices included); the wrapped Err is not copied
+// and stays shared.
+func (e *Error) Clone() *Error {
+	if e == nil {
+		return nil
+	}
+	c := *e
+	if e.Details != nil {
+		c.Details = cloneValue(e.Details).(map[string]any)
+	}
+	if e.SafeDetails != nil {
+		c.SafeDetails = cloneValue(e.SafeDetails).(map[string]any)
+	}
+	return &c
+}
+
+// cloneValue deep-copies the JSON-like containers used in Details.
+func cloneValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, val := range v {
+			m[k] = cloneValue(val)
+		}
+		return m
+	case []any:
+		s := make([]any, len(v))
+		for i, val := range v {
+			s[i] = cloneValue(val)
+		}
+		return s
+	default:
+		return v
+	}
+}
+
 func (e *Error) Unwrap() error {
 	return e.Err
 }
@@ -127,21 +256,184 @@ func (e *Error) Is(target error) bool {
 	return false
 }
 
-// MarshalJSON for RFC 7807 compatibility
+// defaultSafeMessages holds messages set with SetDefaultSafeMessage.
+var defaultSafeMessages sync.Map // Code -> string
+
+// SetDefaultSafeMessage sets the public message used for errors of code that
+// have no SafeMsg, e.g. "The requested resource was not found" for NotFound.
+// Passing an empty msg removes the default.
+func SetDefaultSafeMessage(code Code, msg string) {
+	if msg == "" {
+		defaultSafeMessages.Delete(code)
+		return
+	}
+	defaultSafeMessages.Store(code, msg)
+}
+
+// PublicMessage returns the client-safe message of e: its SafeMsg, else the
+// default set for its code, else the code string. It never returns Msg.
+func (e *Error) PublicMessage() string {
+	if e.SafeMsg != "" {
+		return e.SafeMsg
+	}
+	if msg, ok := defaultSafeMessages.Load(e.Code); ok {
+		return msg.(string)
+	}
+	return e.Code.String()
+}
+
+// JSONFields names the owl specific members of an Error's JSON form. The
+// RFC 7807 members (type, title, status, instance) keep their standard names.
+type JSONFields struct {
+	Code    string // The code string, e.g. "NOT_FOUND"
+	Message string // The public message
+	Details string // The SafeDetails
+}
+
+var (
+	// DefaultJSONFields are the member names used unless SetJSONFields is called.
+	DefaultJSONFields = JSONFields{Code: "code", Message: "message", Details: "details"}
+
+	// ProblemJSONFields sends the public message as the RFC 7807 "detail" member.
+	ProblemJSONFields = JSONFields{Code: "code", Message: "detail", Details: "details"}
+)
+
+// jsonFields holds the names set with SetJSONFields.
+var jsonFields atomic.Pointer[JSONFields]
+
+// reservedJSONMembers are the member names of an Error's JSON form that are
+// not configurable.
+var reservedJSONMembers = []string{"type", "title", "status", "instance", "op", "safe_message"}
+
+// SetJSONFields sets the member names used to marshal and unmarshal errors,
+// e.g. JSONFields{Code: "errorCode", Message: "errorMessage", Details: "errorDetails"}.
+// Empty names keep their DefaultJSONFields value. Call it during
+// initialization, with the same names on servers and their clients.
+//
+// Names that are reserved (type, title, status, instance, op, safe_message) or
+// used twice are rejected with an Invalid error, leaving the names unchanged.
+func SetJSONFields(f JSONFields) error {
+	if f.Code == "" {
+		f.Code = DefaultJSONFields.Code
+	}
+	if f.Message == "" {
+		f.Message = DefaultJSONFields.Message
+	}
+	if f.Details == "" {
+		f.Details = DefaultJSONFields.Details
+	}
+	if f.Code == f.Message || f.Code == f.Details || f.Message == f.Details {
+		return Problem(Invalid, WithMsg(fmt.Sprintf("json fields %+v: duplicate name", f)))
+	}
+	for _, name := range []string{f.Code, f.Message, f.Details} {
+		if slices.Contains(reservedJSONMembers, name) {
+			return Problem(Invalid, WithMsg(fmt.Sprintf("json fields %+v: %q is reserved", f, name)))
+		}
+	}
+	jsonFields.Store(&f)
+	return nil
+}
+
+// CurrentJSONFields returns the member names set with SetJSONFields.
+func CurrentJSONFields() JSONFields {
+	if f := jsonFields.Load(); f != nil {
+		return *f
+	}
+	return DefaultJSONFields
+}
+
+// MarshalJSON for RFC 7807 compatibility.
+// The owl specific code/message members are always present for existing consumers,
+// under the names set with SetJSONFields.
+// Only SafeDetails are emitted, as the details member; Details are internal.
 func (e *Error) MarshalJSON() ([]byte, error) {
-	safeMsg := e.SafeMsg
-	if safeMsg == "" {
-		safeMsg = e.Code.String()
-	}
-	return json.Marshal(&struct {
-		Code    string         `json:"code"`
-		Message string         `json:"message"`
-		Details map[string]any `json:"details,omitempty"`
+	fields := CurrentJSONFields()
+	members := []struct {
+		key   string
+		value any
+		omit  bool
+	}{
+		{"type", e.Type, e.Type == ""},
+		{"title", e.Title, e.Title == ""},
+		{"status", ToHTTPStatus(e), false},
+		{"instance", e.Instance, e.Instance == ""},
+		{fields.Code, e.Code.String(), false},
+		{fields.Message, e.PublicMessage(), false},
+		{fields.Details, e.SafeDetails, len(e.SafeDetails) == 0},
+	}
+
+	// Built by hand to keep the members in order under configurable names
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for _, m := range members {
+		if m.omit {
+			continue
+		}
+		key, err := json.Marshal(m.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON reads an error written by MarshalJSON, looking up the code,
+// message and details under the names set with SetJSONFields. The message
+// becomes Msg and the details SafeDetails.
+func (e *Error) UnmarshalJSON(b []byte) error {
+	var std struct {
+		SafeMsg  *string `json:"safe_message"`
+		Op       *string `json:"op"`
+		Type     *string `json:"type"`
+		Title    *string `json:"title"`
+		Instance *string `json:"instance"`
+	}
+	if err := json.Unmarshal(b, &std); err != nil {
+		return err
+	}
+	for _, m := range []struct {
+		src *string
+		dst *string
 	}{
-		Code:    e.Code.String(),
-		Message: safeMsg,
-		Details: e.Details,
-	})
+		{std.SafeMsg, &e.SafeMsg},
+		{std.Op, &e.Op},
+		{std.Type, &e.Type},
+		{std.Title, &e.Title},
+		{std.Instance, &e.Instance},
+	} {
+		if m.src != nil {
+			*m.dst = *m.src
+		}
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(b, &members); err != nil {
+		return err
+	}
+	fields := CurrentJSONFields()
+	for key, dst := range map[string]any{
+		fields.Code:    &e.Code,
+		fields.Message: &e.Msg,
+		fields.Details: &e.SafeDetails,
+	} {
+		if raw, ok := members[key]; ok {
+			if err := json.Unmarshal(raw, dst); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
 // Logger interface
@@ -150,16 +442,86 @@ type Logger interface {
 	Info(ctx context.Context, msg string, args ...any)
 	Warn(ctx context.Context, msg string, args ...any)
 	Error(ctx context.Context, msg string, err error, args ...any)
+
+	// With returns a logger that adds the key-value pairs args to every entry,
+	// e.g. logger.With("service", "billing", "version", v). The receiver is not
+	// modified, and both loggers are safe for concurrent use.
+	With(args ...any) Logger
 }
 
 // Monitor interface
 type Monitor interface {
 	Counter(name string, opts ...MetricOption) Counter
 	Histogram(name string, opts ...MetricOption) Histogram
+	Gauge(name string, opts ...MetricOption) Gauge
+	UpDownCounter(name string, opts ...MetricOption) UpDownCounter
+
+	// ObservableGauge registers callback to be sampled whenever metrics are
+	// collected, for values that can only be observed (e.g. goroutine count or
+	// pool size). callback must be safe for concurrent use.
+	ObservableGauge(name string, callback func(ctx context.Context) float64, opts ...MetricOption)
 }
 
+// MetricOption configures an instrument. Adapters resolve options with NewMetricConfig.
 type MetricOption func(any)
 
+// MetricConfig holds the settings collected from MetricOptions.
+type MetricConfig struct {
+	Description string
+	Unit        string
+	Buckets     []float64 // Histogram bucket boundaries; ignored by other instruments
+	Int64       bool      // Integer counter or histogram, for adapters that distinguish them
+}
+
+// NewMetricConfig applies opts to an empty MetricConfig.
+func NewMetricConfig(opts ...MetricOption) MetricConfig {
+	var cfg MetricConfig
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	return cfg
+}
+
+// WithDescription sets the instrument description.
+func WithDescription(desc string) MetricOption {
+	return func(c any) {
+		if cfg, ok := c.(*MetricConfig); ok {
+			cfg.Description = desc
+		}
+	}
+}
+
+// WithUnit sets the instrument unit (e.g. "s", "By").
+func WithUnit(unit string) MetricOption {
+	return func(c any) {
+		if cfg, ok := c.(*MetricConfig); ok {
+			cfg.Unit = unit
+		}
+	}
+}
+
+// WithBuckets sets explicit bucket boundaries. It only applies to histograms.
+func WithBuckets(buckets []float64) MetricOption {
+	return func(c any) {
+		if cfg, ok := c.(*MetricConfig); ok {
+			cfg.Buckets = buckets
+		}
+	}
+}
+
+// WithInt64 backs a counter or histogram with an integer instrument, for
+// naturally integral values such as request counts or byte sizes. Values are
+// rounded to the nearest integer. Adapters without integer instruments ignore it.
+func WithInt64() MetricOption {
+	return func(c any) {
+		if cfg, ok := c.(*MetricConfig); ok {
+			cfg.Int64 = true
+		}
+	}
+}
+
 // Attribute represents a metric tag/label
 type Attribute struct {
 	Key   string
@@ -171,11 +533,24 @@ func Attr(k, v string) Attribute {
 	return Attribute{Key: k, Value: v}
 }
 
+// Counter is a monotonic sum. Deltas are float64; whether the backend keeps
+// them as floats or integers (see WithInt64) depends on the adapter.
 type Counter interface {
 	Inc(ctx context.Context, attrs ...Attribute)
 	Add(ctx context.Context, delta float64, attrs ...Attribute)
 }
 
+// Histogram records a distribution of values, e.g. latencies.
 type Histogram interface {
 	Record(ctx context.Context, value float64, attrs ...Attribute)
 }
+
+// Gauge records the current value of something, e.g. a queue depth.
+type Gauge interface {
+	Set(ctx context.Context, value float64, attrs ...Attribute)
+}
+
+// UpDownCounter is a counter that can go down, e.g. in-flight requests.
+type UpDownCounter interface {
+	Add(ctx context.Context, delta float64, attrs ...Attribute)
+}
@@ -1,8 +1,13 @@
 package owl
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -16,6 +21,11 @@ func TestCode_String(t *testing.T) {
 		{CodeUnauthorized, "UNAUTHORIZED"},
 		{CodePermissionDenied, "PERMISSION_DENIED"},
 		{CodeNotFound, "NOT_FOUND"},
+		{CodeAlreadyExists, "ALREADY_EXISTS"},
+		{CodeFailedPrecondition, "FAILED_PRECONDITION"},
+		{CodeResourceExhausted, "RESOURCE_EXHAUSTED"},
+		{CodeCanceled, "CANCELED"},
+		{CodeAborted, "ABORTED"},
 		{CodeInternal, "INTERNAL"},
 		{CodeUnavailable, "UNAVAILABLE"},
 		{CodeDeadlineExceeded, "DEADLINE_EXCEEDED"},
@@ -41,6 +51,11 @@ func TestCode_JSON(t *testing.T) {
 	}{
 		{`"OK"`, CodeOK},
 		{`"INVALID"`, CodeInvalid},
+		{`"ALREADY_EXISTS"`, CodeAlreadyExists},
+		{`"FAILED_PRECONDITION"`, CodeFailedPrecondition},
+		{`"RESOURCE_EXHAUSTED"`, CodeResourceExhausted},
+		{`"CANCELED"`, CodeCanceled},
+		{`"ABORTED"`, CodeAborted},
 		{`"UNKNOWN"`, CodeUnknown},
 		{`"FOOBAR"`, CodeUnknown},
 	}
@@ -91,6 +106,34 @@ func TestProblem_New(t *testing.T) {
 	}
 }
 
+func TestWrap(t *testing.T) {
+	inner := Problem(CodeNotFound, WithMsg("row missing"), WithSafeMsg("user not found"))
+	e := Wrap(fmt.Errorf("repo: %w", inner), WithOp("User.Get"), WithField("user_id", "42"))
+	if e.Code != CodeNotFound || e.SafeMsg != "user not found" {
+		t.Errorf("Expected code and safe message of the wrapped error, got %v %q", e.Code, e.SafeMsg)
+	}
+	if e.Op != "User.Get" || e.Details["user_id"] != "42" {
+		t.Errorf("Expected options to be applied, got %+v", e)
+	}
+	if !errors.Is(e, inner) {
+		t.Error("Expected the original error in the chain")
+	}
+
+	if e := Wrap(inner, WithSafeMsg("no such account")); e.SafeMsg != "no such account" {
+		t.Errorf("Expected WithSafeMsg to override, got %q", e.SafeMsg)
+	}
+
+	cause := errors.New("connection reset")
+	e = Wrap(cause, WithOp("User.Get"))
+	if e.Code != CodeInternal || e.SafeMsg != "" || e.Err != cause {
+		t.Errorf("Expected Internal wrapping a plain error, got %+v", e)
+	}
+
+	if Wrap(nil) != nil {
+		t.Error("Expected Wrap(nil) to return nil")
+	}
+}
+
 func TestProblem_WithDetails(t *testing.T) {
 	e := Problem(CodeInternal, WithDetails(map[string]any{"k": "v"}))
 	if e.Details["k"] != "v" {
@@ -104,3 +147,208 @@ func TestProblem_WithDetails(t *testing.T) {
 		t.Error("Details not appended")
 	}
 }
+
+func TestProblem_WithSafeDetails(t *testing.T) {
+	e := Problem(CodeInvalid,
+		WithSafeDetails(map[string]any{"field": "email"}),
+		WithDetails(map[string]any{"raw_input": "not-an-email"}),
+	)
+	b, err := json.Marshal(e)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var body map[string]any
+	json.Unmarshal(b, &body)
+	details, _ := body["details"].(map[string]any)
+	if details["field"] != "email" {
+		t.Errorf("Expected safe details in JSON, got %s", b)
+	}
+	if strings.Contains(string(b), "raw_input") || strings.Contains(string(b), "not-an-email") {
+		t.Errorf("Internal details leaked into JSON: %s", b)
+	}
+
+	c := e.Clone()
+	c.SafeDetails["field"] = "name"
+	if e.SafeDetails["field"] != "email" {
+		t.Error("Expected Clone to copy SafeDetails")
+	}
+}
+
+func TestError_MarshalProblemDetails(t *testing.T) {
+	e := Problem(CodeNotFound,
+		WithType("https://example.com/probs/missing"),
+		WithTitle("Resource missing"),
+		WithInstance("/items/42"),
+		WithSafeMsg("item not found"),
+	)
+	if !e.IsProblemDetails() {
+		t.Error("Expected IsProblemDetails")
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var got map[string]any
+	json.Unmarshal(b, &got)
+
+	want := map[string]any{
+		"type":     "https://example.com/probs/missing",
+		"title":    "Resource missing",
+		"status":   float64(404),
+		"instance": "/items/42",
+		"code":     "NOT_FOUND",
+		"message":  "item not found",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %v, want %v", k, got[k], v)
+		}
+	}
+
+	if Problem(CodeNotFound).IsProblemDetails() {
+		t.Error("Plain error should not report problem details")
+	}
+}
+
+func TestSetJSONFields(t *testing.T) {
+	if err := SetJSONFields(JSONFields{Code: "errorCode", Message: "errorMessage", Details: "errorDetails"}); err != nil {
+		t.Fatalf("SetJSONFields failed: %v", err)
+	}
+	t.Cleanup(func() { SetJSONFields(DefaultJSONFields) })
+
+	e := Problem(CodeInvalid, WithSafeMsg("invalid email"), WithSafeDetails(map[string]any{"field": "email"}))
+	b, err := json.Marshal(e)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if want := `{"status":400,"errorCode":"INVALID","errorMessage":"invalid email","errorDetails":{"field":"email"}}`; string(b) != want {
+		t.Errorf("Marshal = %s, want %s", b, want)
+	}
+
+	var got Error
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if got.Code != CodeInvalid || got.Msg != "invalid email" || got.SafeDetails["field"] != "email" {
+		t.Errorf("Expected renamed fields to hydrate, got %+v", got)
+	}
+
+	// Clients decoding with the same names get the server's error back
+	resp := &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(b)),
+	}
+	if fe := FromResponse(resp); fe.Code != CodeInvalid || fe.Msg != "invalid email" {
+		t.Errorf("FromResponse = %+v", fe)
+	}
+
+	SetJSONFields(ProblemJSONFields)
+	b, _ = json.Marshal(e)
+	if !strings.Contains(string(b), `"detail":"invalid email"`) {
+		t.Errorf("Expected the RFC 7807 detail member, got %s", b)
+	}
+
+	// Colliding names are rejected and leave the names unchanged
+	for _, f := range []JSONFields{
+		{Code: "status"},
+		{Message: "type"},
+		{Details: "code"},
+		{Code: "error", Message: "error"},
+	} {
+		if err := SetJSONFields(f); !errors.Is(err, Invalid) {
+			t.Errorf("SetJSONFields(%+v) = %v, want Invalid", f, err)
+		}
+	}
+	if CurrentJSONFields() != ProblemJSONFields {
+		t.Errorf("Expected rejected names not to apply, got %+v", CurrentJSONFields())
+	}
+}
+
+func TestError_Clone(t *testing.T) {
+	cause := errors.New("db down")
+	base := Problem(CodeNotFound,
+		WithSafeMsg("resource not found"),
+		WithErr(cause),
+		WithDetails(map[string]any{"scope": "users", "tags": []any{"a"}, "meta": map[string]any{"v": 1}}),
+	)
+
+	c := base.Clone()
+	WithDetails(map[string]any{"user_id": "42"})(c)
+	c.Details["meta"].(map[string]any)["v"] = 2
+	c.Details["tags"].([]any)[0] = "b"
+	c.SafeMsg = "user not found"
+
+	if _, ok := base.Details["user_id"]; ok {
+		t.Error("Expected clone details not to leak into the original")
+	}
+	if base.Details["meta"].(map[string]any)["v"] != 1 || base.Details["tags"].([]any)[0] != "a" {
+		t.Errorf("Expected nested details to be deep-copied, got %v", base.Details)
+	}
+	if base.SafeMsg != "resource not found" {
+		t.Errorf("Expected original SafeMsg to be unchanged, got %q", base.SafeMsg)
+	}
+	if c.Code != CodeNotFound || c.Err != cause {
+		t.Errorf("Expected code and wrapped error to be copied, got %v %v", c.Code, c.Err)
+	}
+
+	var nilErr *Error
+	if nilErr.Clone() != nil {
+		t.Error("Expected nil clone of nil error")
+	}
+}
+
+func TestWithField(t *testing.T) {
+	e := Problem(CodeNotFound,
+		WithDetails(map[string]any{"scope": "users"}),
+		WithField("user_id", "42"),
+	)
+	if e.Details["user_id"] != "42" || e.Details["scope"] != "users" {
+		t.Errorf("Expected merged details, got %v", e.Details)
+	}
+
+	e = New(CodeNotFound, "user missing", WithField("user_id", 7))
+	if e.Msg != "user missing" || e.Details["user_id"] != 7 {
+		t.Errorf("Expected WithField to work with New, got %+v", e)
+	}
+}
+
+func TestAsError(t *testing.T) {
+	base := Problem(CodeNotFound)
+	if e, ok := AsError(fmt.Errorf("wrapped: %w", base)); !ok || e != base {
+		t.Errorf("Expected wrapped *Error, got %v %v", e, ok)
+	}
+	if e, ok := AsError(errors.New("plain")); ok || e != nil {
+		t.Errorf("Expected no *Error, got %v", e)
+	}
+	if _, ok := AsError(nil); ok {
+		t.Error("Expected nil error not to match")
+	}
+}
+
+func TestSetDefaultSafeMessage(t *testing.T) {
+	SetDefaultSafeMessage(CodeNotFound, "The requested resource was not found")
+	defer SetDefaultSafeMessage(CodeNotFound, "")
+
+	b, err := json.Marshal(Problem(CodeNotFound, WithMsg("user 42 not in db")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body map[string]any
+	json.Unmarshal(b, &body)
+	if body["message"] != "The requested resource was not found" {
+		t.Errorf("Expected default safe message, got %v", body["message"])
+	}
+
+	// An explicit SafeMsg wins, and other codes still fall back to the code string
+	if msg := Problem(CodeNotFound, WithSafeMsg("user not found")).PublicMessage(); msg != "user not found" {
+		t.Errorf("Expected explicit SafeMsg, got %q", msg)
+	}
+	if msg := Problem(CodeInvalid, WithMsg("internal")).PublicMessage(); msg != CodeInvalid.String() {
+		t.Errorf("Expected code string fallback, got %q", msg)
+	}
+	if st := ToGRPCStatus(Problem(CodeNotFound)); st.Message() != "The requested resource was not found" {
+		t.Errorf("Expected default safe message in gRPC status, got %q", st.Message())
+	}
+}
@@ -0,0 +1,58 @@
+package owl
+
+import "fmt"
+
+// ValidationError accumulates field violations into a single Invalid error.
+// The owl.Invalid name is taken by the code alias, hence the constructor name.
+//
+//	v := owl.NewValidationError()
+//	if req.Email == "" {
+//		v.AddField("email", "required")
+//	}
+//	if err := v.Err(); err != nil {
+//		return err
+//	}
+type ValidationError struct {
+	fields []any // map[string]any{"field": ..., "reason": ...}, JSON- and structpb-friendly
+	opts   []Option
+}
+
+// NewValidationError starts an empty validation error. opts are applied to
+// the resulting *Error, after the defaults, e.g. WithOp or WithSafeMsg.
+func NewValidationError(opts ...Option) *ValidationError {
+	return &ValidationError{opts: opts}
+}
+
+// AddField records that field failed validation for reason. It returns v for chaining.
+func (v *ValidationError) AddField(field, reason string) *ValidationError {
+	v.fields = append(v.fields, map[string]any{"field": field, "reason": reason})
+	return v
+}
+
+// HasErrors reports whether any field violation was added.
+func (v *ValidationError) HasErrors() bool {
+	return len(v.fields) > 0
+}
+
+// Problem builds the *Error: code Invalid, with the violations as the client
+// facing detail {"fields":[{"field":"email","reason":"required"}, ...]}.
+func (v *ValidationError) Problem() *Error {
+	fields := make([]any, len(v.fields))
+	for i, f := range v.fields {
+		fields[i] = cloneValue(f)
+	}
+	opts := append([]Option{
+		WithMsg(fmt.Sprintf("validation failed: %d field(s)", len(fields))),
+		WithSafeMsg("validation failed"),
+		WithSafeDetails(map[string]any{"fields": fields}),
+	}, v.opts...)
+	return Problem(CodeInvalid, opts...)
+}
+
+// Err returns Problem, or nil if no field violation was added.
+func (v *ValidationError) Err() error {
+	if !v.HasErrors() {
+		return nil
+	}
+	return v.Problem()
+}
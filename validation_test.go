@@ -0,0 +1,42 @@
+package owl
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestValidationError(t *testing.T) {
+	v := NewValidationError(WithOp("User.Create"))
+	if v.Err() != nil {
+		t.Fatal("Expected nil error without violations")
+	}
+
+	err := v.AddField("email", "required").
+		AddField("age", "must be positive").
+		AddField("name", "too long").
+		Err()
+	if !errors.Is(err, CodeInvalid) {
+		t.Fatalf("Expected an Invalid error, got %v", err)
+	}
+	if ToHTTPStatus(err) != 400 {
+		t.Errorf("Expected 400, got %d", ToHTTPStatus(err))
+	}
+
+	b, _ := json.Marshal(err)
+	want := `{"status":400,"code":"INVALID","message":"validation failed","details":{"fields":[` +
+		`{"field":"email","reason":"required"},` +
+		`{"field":"age","reason":"must be positive"},` +
+		`{"field":"name","reason":"too long"}]}}`
+	if string(b) != want {
+		t.Errorf("Unexpected JSON:\n got %s\nwant %s", b, want)
+	}
+
+	e, _ := AsError(err)
+	if e.Op != "User.Create" {
+		t.Errorf("Expected options to apply, got op %q", e.Op)
+	}
+	if st := ToGRPCStatus(err); len(st.Details()) != 1 {
+		t.Errorf("Expected fields to survive as a gRPC status detail, got %v", st.Details())
+	}
+}
@@ -49,7 +49,8 @@ func TestErrorToGRPCStatus(t *testing.T) {
 		want codes.Code
 	}{
 		{"nil", nil, codes.OK},
-		{"generic error", context.DeadlineExceeded, codes.Unknown},
+		{"generic error", errors.New("boom"), codes.Unknown},
+		{"context deadline", context.DeadlineExceeded, codes.DeadlineExceeded},
 		{"owl invalid", owl.Problem(owl.Invalid, owl.WithMsg("bad request")), codes.InvalidArgument},
 		{"owl not found", owl.Problem(owl.NotFound, owl.WithMsg("not found")), codes.NotFound},
 	}
@@ -257,20 +258,32 @@ func TestOwlGoSafety(t *testing.T) {
 }
 
 func TestOwlStart(t *testing.T) {
-	ctx := context.Background()
-
-	// We can't easily assert OTel internal state without a global span exporter hook or mocking the TracerProvider.
-	// But we can check that it doesn't panic and returns a valid context.
-	ctx, end := owl.Start(ctx, "TestSpan")
-	defer func() {
-		// Test error recording
-		err := errors.New("span error")
-		end(&err)
-	}()
+	tp := owltest.NewTracerProvider()
+	owl.SetTracerProvider(tp)
+	defer owl.SetTracerProvider(nil)
 
+	ctx, end := owl.Start(context.Background(), "TestSpan")
 	if ctx == nil {
 		t.Error("expected non-nil context")
 	}
+
+	// Test error recording
+	err := errors.New("span error")
+	end(&err)
+
+	if n := len(tp.RecordedSpans()); n != 1 {
+		t.Fatalf("expected 1 span, got %d", n)
+	}
+	span := tp.SpanByName("TestSpan")
+	if span == nil {
+		t.Fatalf("expected span named TestSpan, got %v", tp.RecordedSpans())
+	}
+	if span.Status().Description != "span error" {
+		t.Errorf("expected error status, got %+v", span.Status())
+	}
+	if len(span.Events()) != 1 || span.Events()[0].Name != "exception" {
+		t.Errorf("expected recorded error event, got %v", span.Events())
+	}
 }
 
 func TestHealthPackage(t *testing.T) {
@@ -0,0 +1,56 @@
+package owl
+
+import (
+	"context"
+	"sync"
+)
+
+// Warning is a non-fatal condition attached to an otherwise successful response,
+// such as a deprecation notice.
+type Warning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type warningsKey struct{}
+
+// warningSet collects warnings for a single request.
+type warningSet struct {
+	mu    sync.Mutex
+	items []Warning
+}
+
+// WithWarnings returns a copy of ctx that can collect warnings via AddWarning.
+// The HTTP middleware installs it for every request.
+func WithWarnings(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(warningsKey{}).(*warningSet); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, warningsKey{}, &warningSet{})
+}
+
+// AddWarning attaches a warning to the request carried by ctx.
+// It is a no-op if ctx was not prepared with WithWarnings.
+func AddWarning(ctx context.Context, code, msg string) {
+	ws, ok := ctx.Value(warningsKey{}).(*warningSet)
+	if !ok {
+		return
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.items = append(ws.items, Warning{Code: code, Message: msg})
+}
+
+// Warnings returns a copy of the warnings collected in ctx.
+func Warnings(ctx context.Context) []Warning {
+	ws, ok := ctx.Value(warningsKey{}).(*warningSet)
+	if !ok {
+		return nil
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if len(ws.items) == 0 {
+		return nil
+	}
+	return append([]Warning(nil), ws.items...)
+}
@@ -0,0 +1,26 @@
+package owl
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWarnings(t *testing.T) {
+	// Without a collector, AddWarning is a no-op
+	AddWarning(context.Background(), "DEPRECATED", "ignored")
+	if ws := Warnings(context.Background()); ws != nil {
+		t.Errorf("Expected no warnings, got %v", ws)
+	}
+
+	ctx := WithWarnings(context.Background())
+	AddWarning(ctx, "DEPRECATED", "use /v2/items")
+	AddWarning(WithWarnings(ctx), "PARTIAL", "2 items skipped") // Re-wrapping keeps the same set
+
+	ws := Warnings(ctx)
+	if len(ws) != 2 {
+		t.Fatalf("Expected 2 warnings, got %d", len(ws))
+	}
+	if ws[0].Code != "DEPRECATED" || ws[1].Message != "2 items skipped" {
+		t.Errorf("Unexpected warnings: %+v", ws)
+	}
+}